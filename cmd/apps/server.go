@@ -5,12 +5,14 @@ import (
 	"interestBar/pkg/conf"
 	"interestBar/pkg/logger"
 	"interestBar/pkg/server/auth"
+	"interestBar/pkg/server/job"
 	"interestBar/pkg/server/router"
 	s3storage "interestBar/pkg/server/storage/s3"
 	"interestBar/pkg/server/storage/db/pgsql"
 	"interestBar/pkg/server/storage/elasticsearch"
 	rabbitmq "interestBar/pkg/server/storage/rabbitmq"
 	"interestBar/pkg/server/storage/redis"
+	"interestBar/pkg/server/subscriber"
 	"os"
 	"os/signal"
 	"syscall"
@@ -50,18 +52,25 @@ func Run(configPath string) {
 	}
 
 	// 8. Init RabbitMQ for async message processing
+	// 连接失败时后台自动重连，期间事件暂存在发件箱中，连上后再投递和消费
 	if err := rabbitmq.InitRabbitMQ(); err != nil {
 		logger.Log.Warn("Failed to initialize RabbitMQ: " + err.Error())
-		logger.Log.Info("Running without RabbitMQ message queue functionality")
-	} else {
-		// 启动消费者处理圈子同步消息
-		go rabbitmq.StartConsumerWithRetry()
+		logger.Log.Info("RabbitMQ will keep reconnecting in background")
 	}
+	// 注册事件订阅者并启动消费
+	subscriber.RegisterAll()
+	rabbitmq.StartConsumer()
 
-	// 9. Init Router
+	// 9. Start background jobs
+	go job.StartPostPublishScheduler()
+	go job.StartCounterFlusher()
+	go job.StartHotScoreJob()
+	go job.StartOutboxRelay()
+
+	// 10. Init Router
 	r := router.InitRouter()
 
-	// 10. Run Server
+	// 11. Run Server
 	addr := fmt.Sprintf(":%d", conf.Config.Server.Port)
 	logger.Log.Info("Server starting on " + addr)
 
@@ -78,6 +87,7 @@ func Run(configPath string) {
 	logger.Log.Info("Shutdown Server ...")
 
 	// Close resources
+	rabbitmq.CloseRabbitMQ()
 	redis.CloseRedis()
 	auth.CloseSaToken()
 	logger.Log.Info("Server shutdown complete")
@@ -21,6 +21,7 @@ type AppConfig struct {
 	S3           S3           `mapstructure:"s3" json:"s3" yaml:"s3"`
 	Elasticsearch Elasticsearch `mapstructure:"elasticsearch" json:"elasticsearch" yaml:"elasticsearch"`
 	RabbitMQ     RabbitMQ     `mapstructure:"rabbitmq" json:"rabbitmq" yaml:"rabbitmq"`
+	HotScore     HotScore     `mapstructure:"hot_score" json:"hot_score" yaml:"hot_score"`
 }
 
 type Server struct {
@@ -33,8 +34,9 @@ type CORS struct {
 }
 
 type App struct {
-	Name    string `mapstructure:"name" json:"name" yaml:"name"`
-	Version string `mapstructure:"version" json:"version" yaml:"version"`
+	Name          string `mapstructure:"name" json:"name" yaml:"name"`
+	Version       string `mapstructure:"version" json:"version" yaml:"version"`
+	InviteBaseURL string `mapstructure:"invite_base_url" json:"invite_base_url" yaml:"invite_base_url"` // 圈子邀请链接前缀，邀请码会拼接在其后
 }
 
 type Log struct {
@@ -108,6 +110,7 @@ type S3 struct {
 type Elasticsearch struct {
 	URL             string `mapstructure:"url" json:"url" yaml:"url"`
 	Index           string `mapstructure:"index" json:"index" yaml:"index"`
+	PostIndex       string `mapstructure:"post_index" json:"post_index" yaml:"post_index"` // 帖子索引名，默认 post
 	RefreshInterval string `mapstructure:"refresh_interval" json:"refresh_interval" yaml:"refresh_interval"`
 }
 
@@ -122,11 +125,47 @@ type RabbitMQ struct {
 	Queue      string      `mapstructure:"queue" json:"queue" yaml:"queue"`
 	RoutingKey string      `mapstructure:"routing_key" json:"routing_key" yaml:"routing_key"`
 	Retry      RabbitMQRetry `mapstructure:"retry" json:"retry" yaml:"retry"`
+	Reconnect  RabbitMQReconnect `mapstructure:"reconnect" json:"reconnect" yaml:"reconnect"`
 }
 
 // RabbitMQRetry RabbitMQ 重试配置
 type RabbitMQRetry struct {
-	MaxAttempts int `mapstructure:"max_attempts" json:"max_attempts" yaml:"max_attempts"`
+	MaxAttempts     int     `mapstructure:"max_attempts" json:"max_attempts" yaml:"max_attempts"`             // 消息最多处理次数(含首次)，超过后进入死信队列
+	InitialInterval int     `mapstructure:"initial_interval" json:"initial_interval" yaml:"initial_interval"` // 首次重试延迟(秒)
+	Multiplier      float64 `mapstructure:"multiplier" json:"multiplier" yaml:"multiplier"`                   // 重试延迟倍数
+	MaxInterval     int     `mapstructure:"max_interval" json:"max_interval" yaml:"max_interval"`             // 最大重试延迟(秒)
+}
+
+// RabbitMQReconnect RabbitMQ 断线重连配置
+type RabbitMQReconnect struct {
+	InitialInterval    int `mapstructure:"initial_interval" json:"initial_interval" yaml:"initial_interval"`             // 首次重连间隔(秒)，之后每次翻倍
+	MaxInterval        int `mapstructure:"max_interval" json:"max_interval" yaml:"max_interval"`                         // 最大重连间隔(秒)
+	PublishWaitTimeout int `mapstructure:"publish_wait_timeout" json:"publish_wait_timeout" yaml:"publish_wait_timeout"` // 断线时发布消息等待重连的最长时间(秒)
+}
+
+// HotScore 帖子和圈子热度计算配置
+type HotScore struct {
+	Interval       int            `mapstructure:"interval" json:"interval" yaml:"interval"`                         // 计算间隔(秒)
+	WindowDays     int            `mapstructure:"window_days" json:"window_days" yaml:"window_days"`                // 统计窗口(天)，窗口外的帖子热度归零
+	PostHalfLife   float64        `mapstructure:"post_half_life" json:"post_half_life" yaml:"post_half_life"`       // 帖子热度半衰期(小时)
+	CircleHalfLife float64        `mapstructure:"circle_half_life" json:"circle_half_life" yaml:"circle_half_life"` // 圈子活跃度半衰期(小时)
+	Post           HotScorePost   `mapstructure:"post" json:"post" yaml:"post"`
+	Circle         HotScoreCircle `mapstructure:"circle" json:"circle" yaml:"circle"`
+}
+
+// HotScorePost 帖子热度权重
+type HotScorePost struct {
+	View    float64 `mapstructure:"view" json:"view" yaml:"view"`          // 每次浏览
+	Like    float64 `mapstructure:"like" json:"like" yaml:"like"`          // 每个点赞
+	Comment float64 `mapstructure:"comment" json:"comment" yaml:"comment"` // 每条评论
+	Collect float64 `mapstructure:"collect" json:"collect" yaml:"collect"` // 每次收藏
+}
+
+// HotScoreCircle 圈子热度权重
+type HotScoreCircle struct {
+	NewPost      float64 `mapstructure:"new_post" json:"new_post" yaml:"new_post"`                // 每篇新帖
+	ActiveMember float64 `mapstructure:"active_member" json:"active_member" yaml:"active_member"` // 每个活跃成员(发帖或评论)
+	MemberGrowth float64 `mapstructure:"member_growth" json:"member_growth" yaml:"member_growth"` // 每个新加入的成员
 }
 
 func InitConfig(path string) {
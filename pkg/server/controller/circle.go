@@ -4,11 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
 	"interestBar/pkg/server/model"
 	"interestBar/pkg/server/response"
 	"interestBar/pkg/server/storage/db/pgsql"
 	elasticsearch "interestBar/pkg/server/storage/elasticsearch"
-	rabbitmq "interestBar/pkg/server/storage/rabbitmq"
 	"interestBar/pkg/server/utils"
 	"strings"
 	"time"
@@ -59,34 +59,10 @@ func (ctrl *CircleController) CreateCircle(c *gin.Context) {
 		return
 	}
 
-	// 检查圈子名称是否已存在（只检查未删除的）
-	var existingCircle model.Circle
-	checkResult := pgsql.DB.Where("name = ? AND deleted = ?", req.Name, 0).First(&existingCircle)
-	if checkResult.Error == nil {
-		// 找到同名圈子
-		response.Conflict(c, "Circle name already exists")
+	// 检查圈子名称和 slug 是否已存在（只检查未删除的）
+	if !checkCircleUnique(c, strings.TrimSpace(req.Name), strings.TrimSpace(req.Slug), 0) {
 		return
 	}
-	if checkResult.Error != gorm.ErrRecordNotFound {
-		// 数据库查询错误
-		response.InternalError(c, "Failed to check circle name")
-		return
-	}
-
-	// 如果提供了 slug，检查 slug 是否已存在
-	if req.Slug != "" {
-		slug := strings.TrimSpace(req.Slug)
-		var existingSlug model.Circle
-		checkSlugResult := pgsql.DB.Where("slug = ? AND deleted = ?", slug, 0).First(&existingSlug)
-		if checkSlugResult.Error == nil {
-			response.Conflict(c, "Circle slug already exists")
-			return
-		}
-		if checkSlugResult.Error != gorm.ErrRecordNotFound {
-			response.InternalError(c, "Failed to check circle slug")
-			return
-		}
-	}
 
 	// 构建圈子数据模型
 	circle := model.Circle{
@@ -106,47 +82,211 @@ func (ctrl *CircleController) CreateCircle(c *gin.Context) {
 		Deleted:     0,
 	}
 
-	// 使用事务创建圈子并添加创建者为圈主
-	if err := model.CreateCircle(pgsql.DB, &circle); err != nil {
+	// 使用事务创建圈子并添加创建者为圈主，同一事务写入圈子创建事件（异步同步到 Elasticsearch）
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.CreateCircle(tx, &circle)
+	}, event.Circle(event.CircleCreated, &circle))
+	if err != nil {
 		logger.Log.Error("Failed to create circle: " + err.Error())
 		response.InternalError(c, "Failed to create circle")
 		return
 	}
 
-	// 异步同步到 Elasticsearch（通过 RabbitMQ）
-	createTime := circle.CreateTime.Format("2006-01-02T15:04:05Z07:00")
-	if err := rabbitmq.PublishCircleSync(
-		rabbitmq.CircleSyncActionCreate,
-		circle.ID,
-		circle.Name,
-		circle.AvatarURL,
-		circle.Description,
-		circle.Hot,
-		circle.CategoryID,
-		circle.MemberCount,
-		circle.PostCount,
-		createTime,
-		circle.Status,
-		circle.Deleted,
-		circle.JoinType,
-	); err != nil {
-		// 仅记录日志，不影响主流程
-		logger.Log.Error("Failed to publish circle sync message: " + err.Error())
-	}
-
 	// 返回创建成功消息
 	response.SuccessWithMessage(c, "创建圈子成功", nil)
 }
 
+// UpdateCircleRequest 更新圈子资料的请求结构，未传的字段保持不变
+type UpdateCircleRequest struct {
+	CircleID    int64   `json:"circle_id" binding:"required,min=1"`
+	Name        *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Slug        *string `json:"slug" binding:"omitempty,max=60"`
+	AvatarURL   *string `json:"avatar_url" binding:"omitempty,url"`
+	CoverURL    *string `json:"cover_url" binding:"omitempty,url"`
+	Description *string `json:"description" binding:"omitempty,min=1,max=2000"`
+	Rule        *string `json:"rule" binding:"omitempty,max=2000"`
+	CategoryID  *int    `json:"category_id" binding:"omitempty,min=0"`
+	JoinType    *int16  `json:"join_type" binding:"omitempty,min=0,max=2"`
+
+	TrustedSkipReview *bool `json:"trusted_skip_review"` // 信任成员发帖是否免审
+}
+
+// UpdateCircle 更新兴趣圈资料（仅圈主和管理员）
+// POST /circle/update
+func (ctrl *CircleController) UpdateCircle(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req UpdateCircleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Log.Error("Invalid request parameters: " + err.Error())
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 检查管理权限
+	if _, ok := requireCircleAdmin(c, req.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	// 2. 查询圈子当前信息
+	circle, err := model.GetCircleByID(pgsql.DB, req.CircleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Circle not found")
+			return
+		}
+		response.InternalError(c, "Failed to check circle")
+		return
+	}
+
+	// 3. 收集需要更新的字段
+	updates := make(map[string]interface{})
+	name, slug := "", ""
+	if req.Name != nil {
+		name = strings.TrimSpace(*req.Name)
+		if name == "" {
+			response.BadRequest(c, "name cannot be empty")
+			return
+		}
+		if name != circle.Name {
+			updates["name"] = name
+		} else {
+			name = ""
+		}
+	}
+	if req.Slug != nil {
+		slug = strings.TrimSpace(*req.Slug)
+		if slug != circle.Slug {
+			updates["slug"] = slug
+		} else {
+			slug = ""
+		}
+	}
+	if req.AvatarURL != nil {
+		updates["avatar_url"] = *req.AvatarURL
+	}
+	if req.CoverURL != nil {
+		updates["cover_url"] = *req.CoverURL
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		if description == "" {
+			response.BadRequest(c, "description cannot be empty")
+			return
+		}
+		updates["description"] = description
+	}
+	if req.Rule != nil {
+		updates["rule"] = strings.TrimSpace(*req.Rule)
+	}
+	if req.JoinType != nil {
+		updates["join_type"] = *req.JoinType
+	}
+	if req.TrustedSkipReview != nil {
+		var skip int16
+		if *req.TrustedSkipReview {
+			skip = 1
+		}
+		updates["trusted_skip_review"] = skip
+	}
+	if req.CategoryID != nil && *req.CategoryID != circle.CategoryID {
+		// 检查目标分类是否存在且已启用
+		if *req.CategoryID != 0 {
+			category, err := model.GetCategoryByID(pgsql.DB, *req.CategoryID)
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					response.NotFound(c, "Category not found")
+					return
+				}
+				response.InternalError(c, "Failed to check category")
+				return
+			}
+			if category.Status != model.CategoryStatusEnabled {
+				response.BadRequest(c, "Category is disabled")
+				return
+			}
+		}
+		updates["category_id"] = *req.CategoryID
+	}
+
+	if len(updates) == 0 {
+		response.BadRequest(c, "Nothing to update")
+		return
+	}
+
+	// 4. 检查新名称和 slug 是否已被其他圈子使用
+	if !checkCircleUnique(c, name, slug, circle.ID) {
+		return
+	}
+
+	// 5. 更新圈子资料（会同步调整分类的圈子数），同一事务写入圈子更新事件
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.UpdateCircle(tx, circle, updates)
+	}, event.Circle(event.CircleUpdated, circle))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Circle not found")
+			return
+		}
+		logger.Log.Error("Failed to update circle: " + err.Error())
+		response.InternalError(c, "Failed to update circle")
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新圈子成功", nil)
+}
+
+// checkCircleUnique 检查圈子名称和 slug 是否已被其他未删除的圈子占用
+// 为空的字段不检查，excludeID 为当前圈子ID（创建时传 0）；冲突时直接返回错误响应给客户端
+func checkCircleUnique(c *gin.Context, name, slug string, excludeID int64) bool {
+	if name != "" {
+		var existingCircle model.Circle
+		checkResult := pgsql.DB.Where("name = ? AND deleted = ? AND id <> ?", name, 0, excludeID).First(&existingCircle)
+		if checkResult.Error == nil {
+			// 找到同名圈子
+			response.Conflict(c, "Circle name already exists")
+			return false
+		}
+		if checkResult.Error != gorm.ErrRecordNotFound {
+			// 数据库查询错误
+			response.InternalError(c, "Failed to check circle name")
+			return false
+		}
+	}
+
+	if slug != "" {
+		var existingSlug model.Circle
+		checkSlugResult := pgsql.DB.Where("slug = ? AND deleted = ? AND id <> ?", slug, 0, excludeID).First(&existingSlug)
+		if checkSlugResult.Error == nil {
+			response.Conflict(c, "Circle slug already exists")
+			return false
+		}
+		if checkSlugResult.Error != gorm.ErrRecordNotFound {
+			response.InternalError(c, "Failed to check circle slug")
+			return false
+		}
+	}
+
+	return true
+}
+
 // CreatePostRequest 创建帖子的请求结构
 type CreatePostRequest struct {
 	CircleID   int64                  `json:"circle_id" binding:"required,min=1"`
-	Title      string                 `json:"title" binding:"required,min=1,max=200"`
+	Title      string                 `json:"title" binding:"omitempty,max=200"`
 	Content    string                 `json:"content" binding:"omitempty,max=10000"`
 	Summary    string                 `json:"summary" binding:"omitempty,max=500"`
 	Type       int16                  `json:"type" binding:"omitempty,min=1,max=3"`
 	MediaExtra map[string]interface{} `json:"media_extra" binding:"omitempty"`
-	Status     int16                  `json:"status" binding:"omitempty,min=0,max=4"`
+	IsDraft    bool                   `json:"is_draft"`                               // 是否保存为草稿
+	PublishAt  *time.Time             `json:"publish_at"`                             // 定时发布时间，仅草稿有效
+	Poll       *CreatePollRequest     `json:"poll"`                                   // 投票设置，仅投票类型的帖子需要
+	Status     *int16                 `json:"status" binding:"omitempty,min=0,max=4"` // 已弃用，兼容旧客户端：0 等同 is_draft=true，其他值等同发布，最终状态仍由圈子设置决定
 }
 
 // CreatePost 创建帖子
@@ -165,40 +305,120 @@ func (ctrl *CircleController) CreatePost(c *gin.Context) {
 		return
 	}
 
+	// 兼容旧客户端的 status 字段
+	if req.Status != nil && *req.Status == model.PostStatusDraft {
+		req.IsDraft = true
+	}
+
 	// 检查帖子类型，默认为1（图文）
 	postType := req.Type
 	if postType == 0 {
 		postType = model.PostTypeTextImage
 	}
 
-	// 检查帖子状态，默认为2（审核中）
-	postStatus := req.Status
-	if postStatus == 0 {
-		postStatus = model.PostStatusReviewing
+	// 投票帖子必须携带投票设置，其他类型不允许携带
+	var poll *model.Poll
+	var pollOptions []model.PollOption
+	if postType == model.PostTypeVote {
+		if req.Poll == nil {
+			response.BadRequest(c, "poll is required for vote posts")
+			return
+		}
+		if poll, pollOptions, ok = buildPoll(c, req.Poll); !ok {
+			return
+		}
+	} else if req.Poll != nil {
+		response.BadRequest(c, "poll is only allowed for vote posts")
+		return
 	}
 
 	// 如果是草稿，不限制标题和内容
-	if postStatus != model.PostStatusDraft {
-		// 检查圈子ID和标题不能为空
-		if req.CircleID == 0 {
-			response.BadRequest(c, "circle_id is required")
+	if !req.IsDraft && strings.TrimSpace(req.Title) == "" {
+		response.BadRequest(c, "title is required")
+		return
+	}
+	if req.PublishAt != nil {
+		if !req.IsDraft {
+			response.BadRequest(c, "publish_at is only allowed for drafts")
 			return
 		}
-		if req.Title == "" {
-			response.BadRequest(c, "title is required")
+		if !req.PublishAt.After(time.Now()) {
+			response.BadRequest(c, "publish_at must be in the future")
 			return
 		}
 	}
 
+	// 检查圈子和发帖权限
+	circle, member, ok := requireCanPost(c, req.CircleID, int64(userID))
+	if !ok {
+		return
+	}
+
+	// 检查帖子状态：草稿保持草稿，其余由圈子设置决定是否免审
+	postStatus := int16(model.PostStatusDraft)
+	if !req.IsDraft {
+		postStatus = model.DecidePublishStatus(circle, member)
+	}
+
+	// 构建帖子数据模型
+	post := model.Post{
+		CircleID:   req.CircleID,
+		UserID:     int64(userID),
+		Type:       postType,
+		Title:      strings.TrimSpace(req.Title),
+		Summary:    strings.TrimSpace(req.Summary),
+		Content:    req.Content,
+		MediaExtra: req.MediaExtra,
+		Status:     postStatus,
+		PublishAt:  req.PublishAt,
+		Deleted:    0,
+	}
+
+	// 如果没有提供 MediaExtra，设置为空 map
+	if post.MediaExtra == nil {
+		post.MediaExtra = make(model.MediaExtraJSON)
+	}
+
+	// 创建帖子（已发布的帖子会更新圈子的帖子计数），投票帖子同时创建投票和选项
+	// 草稿不对外可见，不产生帖子事件
+	var events []event.Pending
+	if post.Status != model.PostStatusDraft {
+		events = append(events, event.Post(event.PostCreated, &post))
+	}
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		if poll != nil {
+			return model.CreatePostWithPoll(tx, &post, poll, pollOptions)
+		}
+		return model.CreatePost(tx, &post)
+	}, events...)
+	if err != nil {
+		response.InternalError(c, "Failed to create post")
+		return
+	}
+
+	// 返回创建成功消息
+	switch post.Status {
+	case model.PostStatusDraft:
+		response.SuccessWithMessage(c, "草稿已保存", gin.H{"post_id": post.ID, "status": post.Status})
+	case model.PostStatusReviewing:
+		response.SuccessWithMessage(c, "发帖成功，等待审核", gin.H{"post_id": post.ID, "status": post.Status})
+	default:
+		response.SuccessWithMessage(c, "发帖成功", gin.H{"post_id": post.ID, "status": post.Status})
+	}
+}
+
+// requireCanPost 检查圈子是否可以发帖以及用户是否有发帖权限，返回圈子和成员信息
+// 如果不可以，会直接返回错误响应给客户端
+func requireCanPost(c *gin.Context, circleID, userID int64) (*model.Circle, *model.CircleMember, bool) {
 	// 1. 检查是否为圈子成员
-	member, err := model.GetMember(pgsql.DB, req.CircleID, int64(userID))
+	member, err := model.GetMember(pgsql.DB, circleID, userID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			response.Forbidden(c, "You are not a member of this circle")
-			return
+			return nil, nil, false
 		}
 		response.InternalError(c, "Failed to check membership")
-		return
+		return nil, nil, false
 	}
 
 	// 2. 检查成员状态
@@ -206,62 +426,37 @@ func (ctrl *CircleController) CreatePost(c *gin.Context) {
 		switch member.Status {
 		case model.MemberStatusPending:
 			response.Forbidden(c, "Your membership is still pending approval")
-			return
+			return nil, nil, false
 		case model.MemberStatusMuted:
 			// 检查禁言是否已过期
 			if member.MuteEndTime != nil && member.MuteEndTime.After(time.Now()) {
 				response.Forbidden(c, "You are muted until "+member.MuteEndTime.Format("2006-01-02 15:04:05"))
-				return
+				return nil, nil, false
 			}
 		case model.MemberStatusBanned:
 			response.Forbidden(c, "You have been banned from this circle")
-			return
+			return nil, nil, false
 		}
 	}
 
 	// 3. 检查圈子是否存在
-	circle, err := model.GetCircleByID(pgsql.DB, req.CircleID)
+	circle, err := model.GetCircleByID(pgsql.DB, circleID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			response.NotFound(c, "Circle not found")
-			return
+			return nil, nil, false
 		}
 		response.InternalError(c, "Failed to check circle")
-		return
+		return nil, nil, false
 	}
 
 	// 检查圈子状态
 	if circle.Status != model.CircleStatusNormal {
 		response.Forbidden(c, "This circle is not available for posting")
-		return
+		return nil, nil, false
 	}
 
-	// 构建帖子数据模型
-	post := model.Post{
-		CircleID:   req.CircleID,
-		UserID:     int64(userID),
-		Type:       postType,
-		Title:      strings.TrimSpace(req.Title),
-		Summary:    strings.TrimSpace(req.Summary),
-		Content:    req.Content,
-		MediaExtra: req.MediaExtra,
-		Status:     postStatus,
-		Deleted:    0,
-	}
-
-	// 如果没有提供 MediaExtra，设置为空 map
-	if post.MediaExtra == nil {
-		post.MediaExtra = make(model.MediaExtraJSON)
-	}
-
-	// 创建帖子（会更新圈子的帖子计数）
-	if err := model.CreatePost(pgsql.DB, &post); err != nil {
-		response.InternalError(c, "Failed to create post")
-		return
-	}
-
-	// 返回创建成功消息
-	response.SuccessWithMessage(c, "发帖成功", nil)
+	return circle, member, true
 }
 
 // GetCirclesRequest 获取圈子列表的请求结构
@@ -345,6 +540,9 @@ type CircleDetailVO struct {
 	CreateTime  time.Time `json:"create_time"`
 	UpdateTime  time.Time `json:"update_time"`
 
+	// 圈子设置
+	TrustedSkipReview int16 `json:"trusted_skip_review"` // 信任成员发帖免审
+
 	// 用户在圈子的成员信息
 	IsJoined          bool       `json:"is_joined"`                      // 是否已加入圈子
 	MemberRole        int16      `json:"member_role,omitempty"`          // 角色
@@ -352,6 +550,7 @@ type CircleDetailVO struct {
 	MemberMuteEndTime *time.Time `json:"member_mute_end_time,omitempty"` // 禁言结束时间
 	MemberIsTop       int16      `json:"member_is_top,omitempty"`        // 是否置顶显示
 	MemberIsDisturb   int16      `json:"member_is_disturb,omitempty"`    // 消息免打扰
+	MemberIsTrusted   int16      `json:"member_is_trusted,omitempty"`    // 是否为信任成员
 }
 
 // GetCircleDetail 获取兴趣圈详情
@@ -410,16 +609,19 @@ func (ctrl *CircleController) GetCircleDetail(c *gin.Context) {
 		Deleted:     circle.Deleted,
 		CreateTime:  circle.CreateTime,
 		UpdateTime:  circle.UpdateTime,
+
+		TrustedSkipReview: circle.TrustedSkipReview,
 	}
 
-	// 如果用户是圈子成员，添加成员信息
+	// 如果用户有成员记录，添加成员信息；待审核和拉黑的不算已加入
 	if member != nil {
-		vo.IsJoined = true
+		vo.IsJoined = member.Status == model.MemberStatusNormal || member.Status == model.MemberStatusMuted
 		vo.MemberRole = member.Role
 		vo.MemberStatus = member.Status
 		vo.MemberMuteEndTime = member.MuteEndTime
 		vo.MemberIsTop = member.IsTop
 		vo.MemberIsDisturb = member.IsDisturb
+		vo.MemberIsTrusted = member.IsTrusted
 	} else {
 		vo.IsJoined = false
 	}
@@ -0,0 +1,432 @@
+package controller
+
+import (
+	"crypto/rand"
+	"interestBar/pkg/conf"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// inviteCodeAlphabet 邀请码字符集（去掉了易混淆的 0/O、1/I）
+const inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// inviteCodeLength 邀请码长度
+const inviteCodeLength = 10
+
+// inviteCodeMaxAttempts 邀请码冲突时最多生成的次数
+const inviteCodeMaxAttempts = 5
+
+// generateInviteCode 生成随机邀请码
+func generateInviteCode() (string, error) {
+	buf := make([]byte, inviteCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = inviteCodeAlphabet[int(b)%len(inviteCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// CircleInviteVO 邀请VO
+type CircleInviteVO struct {
+	ID           int64      `json:"id"`
+	CircleID     int64      `json:"circle_id"`
+	CreatorID    int64      `json:"creator_id"`
+	Code         string     `json:"code"`
+	Link         string     `json:"link,omitempty"` // 邀请链接，未配置链接前缀时为空
+	TargetUserID int64      `json:"target_user_id"`
+	MaxUses      int        `json:"max_uses"`
+	UsedCount    int        `json:"used_count"`
+	ExpireTime   *time.Time `json:"expire_time,omitempty"`
+	Status       int16      `json:"status"`
+	CreateTime   time.Time  `json:"create_time"`
+}
+
+// newCircleInviteVO 组装邀请VO
+func newCircleInviteVO(invite *model.CircleInvite) CircleInviteVO {
+	vo := CircleInviteVO{
+		ID:           invite.ID,
+		CircleID:     invite.CircleID,
+		CreatorID:    invite.CreatorID,
+		Code:         invite.Code,
+		TargetUserID: invite.TargetUserID,
+		MaxUses:      invite.MaxUses,
+		UsedCount:    invite.UsedCount,
+		ExpireTime:   invite.ExpireTime,
+		Status:       invite.Status,
+		CreateTime:   invite.CreateTime,
+	}
+	if baseURL := conf.Config.App.InviteBaseURL; baseURL != "" {
+		vo.Link = baseURL + invite.Code
+	}
+	return vo
+}
+
+// CreateInviteRequest 创建邀请的请求结构
+type CreateInviteRequest struct {
+	CircleID     int64      `json:"circle_id" binding:"required,min=1"`
+	MaxUses      int        `json:"max_uses" binding:"omitempty,min=0,max=10000"` // 最大使用次数，0=不限
+	ExpireTime   *time.Time `json:"expire_time" binding:"omitempty"`              // 过期时间，不传表示永不过期
+	TargetUserID int64      `json:"target_user_id" binding:"omitempty,min=0"`     // 定向邀请用户ID，0=不限
+}
+
+// CreateInvite 创建圈子邀请码/邀请链接（圈主/管理员）
+// POST /circle/invite/create
+func (ctrl *CircleController) CreateInvite(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 过期时间必须在未来
+	if req.ExpireTime != nil && !req.ExpireTime.After(time.Now()) {
+		response.BadRequest(c, "expire_time must be in the future")
+		return
+	}
+
+	// 检查管理员权限
+	if _, ok := requireCircleAdmin(c, req.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	// 检查定向邀请的用户是否存在
+	if req.TargetUserID > 0 {
+		target, err := model.GetUserByID(pgsql.DB, req.TargetUserID)
+		if err != nil {
+			response.InternalError(c, "Failed to check target user")
+			return
+		}
+		if target == nil {
+			response.NotFound(c, "Target user not found")
+			return
+		}
+	}
+
+	invite := model.CircleInvite{
+		CircleID:     req.CircleID,
+		CreatorID:    int64(userID),
+		TargetUserID: req.TargetUserID,
+		MaxUses:      req.MaxUses,
+		UsedCount:    0,
+		ExpireTime:   req.ExpireTime,
+		Status:       model.CircleInviteStatusActive,
+	}
+
+	// 邀请码与已有邀请码冲突时重新生成
+	for attempt := 1; ; attempt++ {
+		code, err := generateInviteCode()
+		if err != nil {
+			logger.Log.Error("Failed to generate invite code: " + err.Error())
+			response.InternalError(c, "Failed to generate invite code")
+			return
+		}
+		invite.Code = code
+
+		err = model.CreateInvite(pgsql.DB, &invite)
+		if err == nil {
+			break
+		}
+		if model.IsUniqueViolation(err) && attempt < inviteCodeMaxAttempts {
+			continue
+		}
+		logger.Log.Error("Failed to create invite: " + err.Error())
+		response.InternalError(c, "Failed to create invite")
+		return
+	}
+
+	response.SuccessWithMessage(c, "创建邀请成功", newCircleInviteVO(&invite))
+}
+
+// GetInvitesRequest 获取邀请列表的请求结构
+type GetInvitesRequest struct {
+	CircleID int64 `form:"circle_id" binding:"required,min=1"`
+	Page     int   `form:"page"` // 页码，默认1
+	Size     int   `form:"size"` // 每页数量，默认20
+}
+
+// GetInvites 获取圈子的邀请列表（圈主/管理员）
+// GET /circle/invite/list
+func (ctrl *CircleController) GetInvites(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetInvitesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	// 检查管理员权限
+	if _, ok := requireCircleAdmin(c, req.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	invites, total, err := model.GetInvitesByCircle(pgsql.DB, req.CircleID, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get invites: " + err.Error())
+		response.InternalError(c, "Failed to get invites")
+		return
+	}
+
+	list := make([]CircleInviteVO, 0, len(invites))
+	for i := range invites {
+		list = append(list, newCircleInviteVO(&invites[i]))
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// RevokeInviteRequest 撤销邀请的请求结构
+type RevokeInviteRequest struct {
+	InviteID int64 `json:"invite_id" binding:"required,min=1"`
+}
+
+// RevokeInvite 撤销邀请（圈主/管理员）
+// POST /circle/invite/revoke
+func (ctrl *CircleController) RevokeInvite(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req RevokeInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	invite, err := model.GetInviteByID(pgsql.DB, req.InviteID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Invite not found")
+			return
+		}
+		response.InternalError(c, "Failed to get invite")
+		return
+	}
+
+	// 检查管理员权限
+	if _, ok := requireCircleAdmin(c, invite.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	if invite.Status == model.CircleInviteStatusRevoked {
+		response.SuccessWithMessage(c, "邀请已撤销", nil)
+		return
+	}
+
+	if err := model.RevokeInvite(pgsql.DB, invite.ID); err != nil {
+		logger.Log.Error("Failed to revoke invite: " + err.Error())
+		response.InternalError(c, "Failed to revoke invite")
+		return
+	}
+
+	response.SuccessWithMessage(c, "邀请已撤销", nil)
+}
+
+// GetInviteRecordsRequest 获取邀请使用记录的请求结构
+type GetInviteRecordsRequest struct {
+	InviteID int64 `form:"invite_id" binding:"required,min=1"`
+	Page     int   `form:"page"` // 页码，默认1
+	Size     int   `form:"size"` // 每页数量，默认20
+}
+
+// InviteRecordVO 邀请使用记录VO
+type InviteRecordVO struct {
+	UserID     int64     `json:"user_id"`
+	Username   string    `json:"username"`
+	AvatarURL  string    `json:"avatar_url,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// GetInviteRecords 获取邀请的使用记录（圈主/管理员）
+// GET /circle/invite/records
+func (ctrl *CircleController) GetInviteRecords(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetInviteRecordsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	invite, err := model.GetInviteByID(pgsql.DB, req.InviteID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Invite not found")
+			return
+		}
+		response.InternalError(c, "Failed to get invite")
+		return
+	}
+
+	// 检查管理员权限
+	if _, ok := requireCircleAdmin(c, invite.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	records, total, err := model.GetInviteRecords(pgsql.DB, invite.ID, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get invite records: " + err.Error())
+		response.InternalError(c, "Failed to get invite records")
+		return
+	}
+
+	// 批量查询使用者信息
+	userIDs := make([]int64, 0, len(records))
+	for _, r := range records {
+		userIDs = append(userIDs, r.UserID)
+	}
+	users, err := loadUserBriefs(userIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	list := make([]InviteRecordVO, 0, len(records))
+	for _, r := range records {
+		user := users[r.UserID]
+		list = append(list, InviteRecordVO{
+			UserID:     r.UserID,
+			Username:   user.Username,
+			AvatarURL:  user.AvatarURL,
+			CreateTime: r.CreateTime,
+		})
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// RedeemInviteRequest 使用邀请的请求结构
+type RedeemInviteRequest struct {
+	Code string `json:"code" binding:"required,min=1,max=32"`
+}
+
+// RedeemInvite 使用邀请码加入圈子（私密圈子也可加入）
+// POST /circle/invite/redeem
+func (ctrl *CircleController) RedeemInvite(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req RedeemInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 检查邀请是否可用
+	invite, err := model.GetInviteByCode(pgsql.DB, strings.ToUpper(strings.TrimSpace(req.Code)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Invalid invite code")
+			return
+		}
+		response.InternalError(c, "Failed to get invite")
+		return
+	}
+
+	switch {
+	case invite.Status == model.CircleInviteStatusRevoked:
+		response.Forbidden(c, "This invite has been revoked")
+		return
+	case invite.IsExpired():
+		response.Forbidden(c, "This invite has expired")
+		return
+	case invite.IsExhausted():
+		response.Forbidden(c, "This invite has reached its usage limit")
+		return
+	case invite.TargetUserID > 0 && invite.TargetUserID != int64(userID):
+		response.Forbidden(c, "This invite is not for you")
+		return
+	}
+
+	// 2. 检查圈子是否存在
+	circle, err := model.GetCircleByID(pgsql.DB, invite.CircleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Circle not found")
+			return
+		}
+		response.InternalError(c, "Failed to check circle")
+		return
+	}
+
+	// 检查圈子状态
+	if circle.Status != model.CircleStatusNormal {
+		response.Forbidden(c, "This circle is not available for joining")
+		return
+	}
+
+	// 3. 检查是否已是成员（待审核的申请会被邀请直接通过）
+	existing, err := model.GetMember(pgsql.DB, circle.ID, int64(userID))
+	if err != nil && err != gorm.ErrRecordNotFound {
+		response.InternalError(c, "Failed to check membership")
+		return
+	}
+	if existing != nil && existing.Status != model.MemberStatusPending {
+		if existing.Status == model.MemberStatusBanned {
+			response.Forbidden(c, "You have been banned from this circle")
+			return
+		}
+		response.Conflict(c, "You are already a member of this circle")
+		return
+	}
+
+	// 4. 使用邀请加入圈子，同一事务写入成员事件（成员数变更，通知搜索索引等订阅者）
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.RedeemInvite(tx, invite, int64(userID))
+	}, event.Member(event.MemberJoined, circle.ID, int64(userID), model.MemberStatusNormal))
+	if err != nil {
+		if err == model.ErrInviteUnavailable {
+			response.Forbidden(c, "This invite is no longer available")
+			return
+		}
+		// 并发的兑换或加入请求已先一步创建了成员记录
+		if model.IsUniqueViolation(err) {
+			response.Conflict(c, "You are already a member of this circle")
+			return
+		}
+		logger.Log.Error("Failed to redeem invite: " + err.Error())
+		response.InternalError(c, "Failed to join circle")
+		return
+	}
+
+	invalidateHomeFeed(int64(userID))
+
+	response.SuccessWithMessage(c, "加入圈子成功", gin.H{"circle_id": circle.ID})
+}
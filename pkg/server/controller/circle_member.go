@@ -0,0 +1,903 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// JoinCircleRequest 加入圈子的请求结构
+type JoinCircleRequest struct {
+	CircleID int64  `json:"circle_id" binding:"required,min=1"`
+	Message  string `json:"message" binding:"omitempty,max=200"` // 申请留言，仅需审核的圈子使用
+}
+
+// JoinCircle 加入兴趣圈
+// POST /circle/join
+func (ctrl *CircleController) JoinCircle(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req JoinCircleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 检查圈子是否存在
+	circle, err := model.GetCircleByID(pgsql.DB, req.CircleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Circle not found")
+			return
+		}
+		response.InternalError(c, "Failed to check circle")
+		return
+	}
+
+	// 检查圈子状态
+	if circle.Status != model.CircleStatusNormal {
+		response.Forbidden(c, "This circle is not available for joining")
+		return
+	}
+
+	// 2. 检查是否已有成员记录
+	existing, err := model.GetMember(pgsql.DB, req.CircleID, int64(userID))
+	if err != nil && err != gorm.ErrRecordNotFound {
+		response.InternalError(c, "Failed to check membership")
+		return
+	}
+	if existing != nil {
+		switch existing.Status {
+		case model.MemberStatusPending:
+			response.Conflict(c, "Your application is still pending approval")
+		case model.MemberStatusBanned:
+			response.Forbidden(c, "You have been banned from this circle")
+		default:
+			response.Conflict(c, "You are already a member of this circle")
+		}
+		return
+	}
+
+	// 3. 根据加入方式创建成员记录
+	member := model.CircleMember{
+		CircleID:  req.CircleID,
+		UserID:    int64(userID),
+		Role:      model.MemberRoleMember,
+		IsTop:     0,
+		IsDisturb: 0,
+	}
+
+	switch circle.JoinType {
+	case model.CircleJoinTypeDirect:
+		member.Status = model.MemberStatusNormal
+	case model.CircleJoinTypeApproval:
+		member.Status = model.MemberStatusPending
+		member.ApplyMessage = strings.TrimSpace(req.Message)
+	default:
+		response.Forbidden(c, "This circle is invite-only")
+		return
+	}
+
+	// 直接加入时同一事务写入成员事件（成员数变更，通知搜索索引等订阅者），提交申请不产生事件
+	var events []event.Pending
+	if member.Status != model.MemberStatusPending {
+		events = append(events, event.Member(event.MemberJoined, circle.ID, int64(userID), member.Status))
+	}
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.CreateMember(tx, &member)
+	}, events...)
+	if err != nil {
+		// 并发的加入请求已先一步创建了成员记录
+		if model.IsUniqueViolation(err) {
+			response.Conflict(c, "You are already a member of this circle or your application is pending")
+			return
+		}
+		logger.Log.Error("Failed to join circle: " + err.Error())
+		response.InternalError(c, "Failed to join circle")
+		return
+	}
+
+	if member.Status == model.MemberStatusPending {
+		response.SuccessWithMessage(c, "申请已提交，请等待管理员审核", nil)
+		return
+	}
+
+	invalidateHomeFeed(int64(userID))
+
+	response.SuccessWithMessage(c, "加入圈子成功", nil)
+}
+
+// LeaveCircleRequest 退出圈子的请求结构
+type LeaveCircleRequest struct {
+	CircleID int64 `json:"circle_id" binding:"required,min=1"`
+}
+
+// LeaveCircle 退出兴趣圈（待审核状态下为撤回申请）
+// POST /circle/leave
+func (ctrl *CircleController) LeaveCircle(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req LeaveCircleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 检查成员记录
+	member, err := model.GetMember(pgsql.DB, req.CircleID, int64(userID))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "You are not a member of this circle")
+			return
+		}
+		response.InternalError(c, "Failed to check membership")
+		return
+	}
+
+	// 2. 圈主不能直接退出，被拉黑的成员不能通过退出解除拉黑
+	if member.Role == model.MemberRoleOwner {
+		response.Forbidden(c, "The owner cannot leave the circle, please transfer ownership first")
+		return
+	}
+	if member.Status == model.MemberStatusBanned {
+		response.Forbidden(c, "You have been banned from this circle")
+		return
+	}
+
+	// 3. 删除成员记录并更新成员数，撤回申请以外同一事务写入成员退出事件
+	var events []event.Pending
+	if member.Status != model.MemberStatusPending {
+		events = append(events, event.Member(event.MemberLeft, req.CircleID, int64(userID), 0))
+	}
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.RemoveMember(tx, member)
+	}, events...)
+	if err != nil {
+		logger.Log.Error("Failed to leave circle: " + err.Error())
+		response.InternalError(c, "Failed to leave circle")
+		return
+	}
+
+	if member.Status == model.MemberStatusPending {
+		response.SuccessWithMessage(c, "已撤回入圈申请", nil)
+		return
+	}
+
+	response.SuccessWithMessage(c, "退出圈子成功", nil)
+}
+
+// GetApplicationsRequest 获取入圈申请列表的请求结构
+type GetApplicationsRequest struct {
+	CircleID int64 `form:"circle_id" binding:"required,min=1"`
+	Page     int   `form:"page"` // 页码，默认1
+	Size     int   `form:"size"` // 每页数量，默认20
+}
+
+// CircleApplicationVO 入圈申请VO
+type CircleApplicationVO struct {
+	UserID     int64     `json:"user_id"`
+	Username   string    `json:"username"`
+	AvatarURL  string    `json:"avatar_url,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// GetApplications 获取圈子的待审核入圈申请（管理员）
+// GET /circle/apply/list
+func (ctrl *CircleController) GetApplications(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetApplicationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	// 检查管理员权限
+	if _, ok := requireCircleAdmin(c, req.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	members, total, err := model.GetPendingMembers(pgsql.DB, req.CircleID, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get applications: " + err.Error())
+		response.InternalError(c, "Failed to get applications")
+		return
+	}
+
+	// 批量查询申请人信息
+	userIDs := make([]int64, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+	}
+	users, err := loadUserBriefs(userIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get applicant info")
+		return
+	}
+
+	list := make([]CircleApplicationVO, 0, len(members))
+	for _, m := range members {
+		user := users[m.UserID]
+		list = append(list, CircleApplicationVO{
+			UserID:     m.UserID,
+			Username:   user.Username,
+			AvatarURL:  user.AvatarURL,
+			Message:    m.ApplyMessage,
+			CreateTime: m.CreateTime,
+		})
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// ReviewApplicationRequest 审核入圈申请的请求结构
+type ReviewApplicationRequest struct {
+	CircleID int64 `json:"circle_id" binding:"required,min=1"`
+	UserID   int64 `json:"user_id" binding:"required,min=1"`
+}
+
+// ApproveApplication 通过入圈申请（管理员）
+// POST /circle/apply/approve
+func (ctrl *CircleController) ApproveApplication(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req ReviewApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查管理员权限
+	if _, ok := requireCircleAdmin(c, req.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	// 同一事务写入成员事件（成员数变更，通知搜索索引等订阅者）
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.ApproveMember(tx, req.CircleID, req.UserID)
+	}, event.Member(event.MemberJoined, req.CircleID, req.UserID, model.MemberStatusNormal))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Application not found")
+			return
+		}
+		logger.Log.Error("Failed to approve application: " + err.Error())
+		response.InternalError(c, "Failed to approve application")
+		return
+	}
+
+	invalidateHomeFeed(req.UserID)
+
+	response.SuccessWithMessage(c, "已通过入圈申请", nil)
+}
+
+// RejectApplication 拒绝入圈申请（管理员）
+// POST /circle/apply/reject
+func (ctrl *CircleController) RejectApplication(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req ReviewApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查管理员权限
+	if _, ok := requireCircleAdmin(c, req.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	if err := model.RejectMember(pgsql.DB, req.CircleID, req.UserID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Application not found")
+			return
+		}
+		logger.Log.Error("Failed to reject application: " + err.Error())
+		response.InternalError(c, "Failed to reject application")
+		return
+	}
+
+	response.SuccessWithMessage(c, "已拒绝入圈申请", nil)
+}
+
+// requireCircleAdmin 检查用户是否为圈子的管理员或圈主
+// 如果不是，会直接返回错误响应给客户端
+func requireCircleAdmin(c *gin.Context, circleID, userID int64) (*model.CircleMember, bool) {
+	member, err := model.GetMember(pgsql.DB, circleID, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.Forbidden(c, "You are not an admin of this circle")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to check membership")
+		return nil, false
+	}
+
+	if member.Status != model.MemberStatusNormal || member.Role < model.MemberRoleAdmin {
+		response.Forbidden(c, "You are not an admin of this circle")
+		return nil, false
+	}
+
+	return member, true
+}
+
+// GetMembersRequest 获取圈子成员列表的请求结构
+type GetMembersRequest struct {
+	CircleID int64 `form:"circle_id" binding:"required,min=1"`
+	Role     int16 `form:"role" binding:"omitempty,oneof=10 20 30"` // 按角色筛选，不传则查询全部
+	Page     int   `form:"page"`                                    // 页码，默认1
+	Size     int   `form:"size"`                                    // 每页数量，默认20
+}
+
+// CircleMemberVO 圈子成员VO
+type CircleMemberVO struct {
+	UserID      int64      `json:"user_id"`
+	Username    string     `json:"username"`
+	AvatarURL   string     `json:"avatar_url,omitempty"`
+	Role        int16      `json:"role"`
+	Status      int16      `json:"status"`
+	MuteEndTime *time.Time `json:"mute_end_time,omitempty"`
+	JoinTime    time.Time  `json:"join_time"`
+}
+
+// GetMembers 获取圈子成员列表（圈主/管理员）
+// GET /circle/member/list
+func (ctrl *CircleController) GetMembers(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetMembersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	// 检查管理员权限
+	if _, ok := requireCircleAdmin(c, req.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	members, total, err := model.GetMembersByCircleID(pgsql.DB, req.CircleID, req.Role, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get members: " + err.Error())
+		response.InternalError(c, "Failed to get members")
+		return
+	}
+
+	// 批量查询成员信息
+	userIDs := make([]int64, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+	}
+	users, err := loadUserBriefs(userIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get member info")
+		return
+	}
+
+	list := make([]CircleMemberVO, 0, len(members))
+	for _, m := range members {
+		user := users[m.UserID]
+		list = append(list, CircleMemberVO{
+			UserID:      m.UserID,
+			Username:    user.Username,
+			AvatarURL:   user.AvatarURL,
+			Role:        m.Role,
+			Status:      m.Status,
+			MuteEndTime: m.MuteEndTime,
+			JoinTime:    m.CreateTime,
+		})
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// ManageMemberRequest 成员管理操作的通用请求结构
+type ManageMemberRequest struct {
+	CircleID int64  `json:"circle_id" binding:"required,min=1"`
+	UserID   int64  `json:"user_id" binding:"required,min=1"`
+	Reason   string `json:"reason" binding:"required,min=1,max=500"` // 操作理由，记录到管理日志
+}
+
+// MuteMemberRequest 禁言成员的请求结构
+type MuteMemberRequest struct {
+	CircleID int64  `json:"circle_id" binding:"required,min=1"`
+	UserID   int64  `json:"user_id" binding:"required,min=1"`
+	Duration int    `json:"duration" binding:"required,min=1,max=43200"` // 禁言时长(分钟)，最长30天
+	Reason   string `json:"reason" binding:"required,min=1,max=500"`     // 操作理由，记录到管理日志
+}
+
+// PromoteMember 设为管理员（仅圈主）
+// POST /circle/member/promote
+func (ctrl *CircleController) PromoteMember(c *gin.Context) {
+	var req ManageMemberRequest
+	operator, target, ok := bindManageTarget(c, &req)
+	if !ok {
+		return
+	}
+
+	if operator.Role != model.MemberRoleOwner {
+		response.Forbidden(c, "Only the owner can appoint admins")
+		return
+	}
+	if target.Role != model.MemberRoleMember {
+		response.Conflict(c, "The member is already an admin")
+		return
+	}
+	if target.Status != model.MemberStatusNormal {
+		response.Forbidden(c, "Only members in normal status can be appointed as admin")
+		return
+	}
+
+	log := newMemberLog(operator, target, model.MemberActionPromote, req.Reason)
+	if err := model.ChangeMemberRole(pgsql.DB, target, model.MemberRoleAdmin, log); err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已设为管理员", nil)
+}
+
+// DemoteMember 取消管理员（仅圈主）
+// POST /circle/member/demote
+func (ctrl *CircleController) DemoteMember(c *gin.Context) {
+	var req ManageMemberRequest
+	operator, target, ok := bindManageTarget(c, &req)
+	if !ok {
+		return
+	}
+
+	if operator.Role != model.MemberRoleOwner {
+		response.Forbidden(c, "Only the owner can dismiss admins")
+		return
+	}
+	if target.Role != model.MemberRoleAdmin {
+		response.Conflict(c, "The member is not an admin")
+		return
+	}
+
+	log := newMemberLog(operator, target, model.MemberActionDemote, req.Reason)
+	if err := model.ChangeMemberRole(pgsql.DB, target, model.MemberRoleMember, log); err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已取消管理员", nil)
+}
+
+// MuteMember 禁言成员
+// POST /circle/member/mute
+func (ctrl *CircleController) MuteMember(c *gin.Context) {
+	var req MuteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if !requireReason(c, &req.Reason) {
+		return
+	}
+
+	operator, target, ok := loadManageTarget(c, req.CircleID, req.UserID)
+	if !ok {
+		return
+	}
+
+	if target.Status == model.MemberStatusBanned {
+		response.Conflict(c, "The member has been banned")
+		return
+	}
+
+	muteEndTime := time.Now().Add(time.Duration(req.Duration) * time.Minute)
+	log := newMemberLog(operator, target, model.MemberActionMute, req.Reason)
+	log.MuteEndTime = &muteEndTime
+
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.ChangeMemberStatus(tx, target, model.MemberStatusMuted, &muteEndTime, log)
+	}, event.Member(event.MemberStatusChanged, req.CircleID, req.UserID, model.MemberStatusMuted))
+	if err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已禁言至 "+muteEndTime.Format("2006-01-02 15:04:05"), nil)
+}
+
+// BanMember 拉黑成员（保留成员记录，被拉黑的用户无法再次加入）
+// POST /circle/member/ban
+func (ctrl *CircleController) BanMember(c *gin.Context) {
+	var req ManageMemberRequest
+	operator, target, ok := bindManageTarget(c, &req)
+	if !ok {
+		return
+	}
+
+	if target.Status == model.MemberStatusBanned {
+		response.Conflict(c, "The member has already been banned")
+		return
+	}
+
+	log := newMemberLog(operator, target, model.MemberActionBan, req.Reason)
+	// 同一事务写入成员事件（成员数变更，通知搜索索引等订阅者）
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.ChangeMemberStatus(tx, target, model.MemberStatusBanned, nil, log)
+	}, event.Member(event.MemberStatusChanged, req.CircleID, req.UserID, model.MemberStatusBanned))
+	if err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已拉黑该成员", nil)
+}
+
+// KickMember 踢出成员（删除成员记录，用户可重新加入）
+// POST /circle/member/kick
+func (ctrl *CircleController) KickMember(c *gin.Context) {
+	var req ManageMemberRequest
+	operator, target, ok := bindManageTarget(c, &req)
+	if !ok {
+		return
+	}
+
+	log := newMemberLog(operator, target, model.MemberActionKick, req.Reason)
+	// 同一事务写入成员事件（成员数变更，通知搜索索引等订阅者）
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.KickMember(tx, target, log)
+	}, event.Member(event.MemberLeft, req.CircleID, req.UserID, 0))
+	if err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已踢出该成员", nil)
+}
+
+// RestoreMember 解除禁言/拉黑
+// POST /circle/member/restore
+func (ctrl *CircleController) RestoreMember(c *gin.Context) {
+	var req ManageMemberRequest
+	operator, target, ok := bindManageTarget(c, &req)
+	if !ok {
+		return
+	}
+
+	if target.Status == model.MemberStatusNormal {
+		response.Conflict(c, "The member is not muted or banned")
+		return
+	}
+
+	log := newMemberLog(operator, target, model.MemberActionRestore, req.Reason)
+	// 同一事务写入成员事件（解除拉黑会恢复成员数）
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.ChangeMemberStatus(tx, target, model.MemberStatusNormal, nil, log)
+	}, event.Member(event.MemberStatusChanged, req.CircleID, req.UserID, model.MemberStatusNormal))
+	if err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	if target.Status == model.MemberStatusBanned {
+		invalidateHomeFeed(req.UserID)
+	}
+
+	response.SuccessWithMessage(c, "已恢复该成员的正常状态", nil)
+}
+
+// TrustMemberRequest 设置信任成员的请求结构
+type TrustMemberRequest struct {
+	CircleID int64  `json:"circle_id" binding:"required,min=1"`
+	UserID   int64  `json:"user_id" binding:"required,min=1"`
+	Enable   *bool  `json:"enable" binding:"required"`               // true=设为信任成员，false=取消
+	Reason   string `json:"reason" binding:"required,min=1,max=500"` // 操作理由，记录到管理日志
+}
+
+// TrustMember 设置/取消信任成员（圈子开启免审时，信任成员发帖无需审核）
+// POST /circle/member/trust
+func (ctrl *CircleController) TrustMember(c *gin.Context) {
+	var req TrustMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if !requireReason(c, &req.Reason) {
+		return
+	}
+
+	operator, target, ok := loadManageTarget(c, req.CircleID, req.UserID)
+	if !ok {
+		return
+	}
+
+	trusted := *req.Enable
+	if (target.IsTrusted == 1) == trusted {
+		response.Conflict(c, "The member's trust status is unchanged")
+		return
+	}
+	if trusted && target.Status != model.MemberStatusNormal {
+		response.Forbidden(c, "Only members in normal status can be trusted")
+		return
+	}
+
+	action := int16(model.MemberActionUntrust)
+	if trusted {
+		action = model.MemberActionTrust
+	}
+	log := newMemberLog(operator, target, action, req.Reason)
+	if err := model.SetMemberTrusted(pgsql.DB, target, trusted, log); err != nil {
+		respondManageError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "操作成功", nil)
+}
+
+// UpdateMemberSettingRequest 更新个人圈子设置的请求结构（只更新传入的字段）
+type UpdateMemberSettingRequest struct {
+	CircleID  int64 `json:"circle_id" binding:"required,min=1"`
+	IsTop     *bool `json:"is_top"`     // 置顶显示，置顶圈子的帖子在首页信息流中排序提前
+	IsDisturb *bool `json:"is_disturb"` // 消息免打扰，开启后不再接收该圈子的通知，帖子仍出现在首页信息流中
+}
+
+// UpdateMemberSetting 更新自己在圈子中的个人设置（置顶显示、消息免打扰）
+// POST /circle/member/setting
+func (ctrl *CircleController) UpdateMemberSetting(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req UpdateMemberSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	member, err := model.GetMember(pgsql.DB, req.CircleID, int64(userID))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "You are not a member of this circle")
+			return
+		}
+		response.InternalError(c, "Failed to check membership")
+		return
+	}
+	if member.Status != model.MemberStatusNormal && member.Status != model.MemberStatusMuted {
+		response.Forbidden(c, "You are not a member of this circle")
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.IsTop != nil {
+		updates["is_top"] = boolToFlag(*req.IsTop)
+	}
+	if req.IsDisturb != nil {
+		updates["is_disturb"] = boolToFlag(*req.IsDisturb)
+	}
+	if len(updates) == 0 {
+		response.BadRequest(c, "No fields to update")
+		return
+	}
+
+	if err := model.UpdateMemberSettings(pgsql.DB, member.ID, updates); err != nil {
+		logger.Log.Error("Failed to update member setting: " + err.Error())
+		response.InternalError(c, "Failed to update setting")
+		return
+	}
+
+	// 置顶设置影响信息流排序，清除缓存
+	if req.IsTop != nil && (member.IsTop == 1) != *req.IsTop {
+		invalidateHomeFeed(int64(userID))
+	}
+
+	response.SuccessWithMessage(c, "设置已更新", nil)
+}
+
+// boolToFlag 将布尔值转换为 0/1 标记
+func boolToFlag(b bool) int16 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// GetMemberLogsRequest 获取成员管理日志的请求结构
+type GetMemberLogsRequest struct {
+	CircleID int64 `form:"circle_id" binding:"required,min=1"`
+	UserID   int64 `form:"user_id" binding:"omitempty,min=0"` // 按被操作成员筛选，不传则查询全部
+	Page     int   `form:"page"`                              // 页码，默认1
+	Size     int   `form:"size"`                              // 每页数量，默认20
+}
+
+// CircleMemberLogVO 成员管理日志VO
+type CircleMemberLogVO struct {
+	ID          int64       `json:"id"`
+	Operator    UserBriefVO `json:"operator"`
+	Target      UserBriefVO `json:"target"`
+	Action      int16       `json:"action"`
+	Reason      string      `json:"reason"`
+	MuteEndTime *time.Time  `json:"mute_end_time,omitempty"`
+	CreateTime  time.Time   `json:"create_time"`
+}
+
+// GetMemberLogs 获取成员管理日志（圈主/管理员）
+// GET /circle/member/logs
+func (ctrl *CircleController) GetMemberLogs(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetMemberLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	// 检查管理员权限
+	if _, ok := requireCircleAdmin(c, req.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	logs, total, err := model.GetMemberLogs(pgsql.DB, req.CircleID, req.UserID, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get member logs: " + err.Error())
+		response.InternalError(c, "Failed to get member logs")
+		return
+	}
+
+	// 批量查询操作人和被操作成员信息
+	userIDs := make([]int64, 0, len(logs)*2)
+	for _, l := range logs {
+		userIDs = append(userIDs, l.OperatorID, l.TargetUserID)
+	}
+	users, err := loadUserBriefs(userIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	list := make([]CircleMemberLogVO, 0, len(logs))
+	for _, l := range logs {
+		list = append(list, CircleMemberLogVO{
+			ID:          l.ID,
+			Operator:    users[l.OperatorID],
+			Target:      users[l.TargetUserID],
+			Action:      l.Action,
+			Reason:      l.Reason,
+			MuteEndTime: l.MuteEndTime,
+			CreateTime:  l.CreateTime,
+		})
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// bindManageTarget 解析通用成员管理请求并加载操作人与被操作成员
+func bindManageTarget(c *gin.Context, req *ManageMemberRequest) (*model.CircleMember, *model.CircleMember, bool) {
+	if err := c.ShouldBindJSON(req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return nil, nil, false
+	}
+	if !requireReason(c, &req.Reason) {
+		return nil, nil, false
+	}
+	return loadManageTarget(c, req.CircleID, req.UserID)
+}
+
+// requireReason 去掉操作理由首尾的空白，为空时返回错误响应给客户端
+func requireReason(c *gin.Context, reason *string) bool {
+	*reason = strings.TrimSpace(*reason)
+	if *reason == "" {
+		response.BadRequest(c, "Reason is required")
+		return false
+	}
+	return true
+}
+
+// loadManageTarget 加载操作人与被操作成员，并校验层级关系
+// 管理员只能管理普通成员，圈主可以管理管理员和普通成员，任何人都不能管理自己
+func loadManageTarget(c *gin.Context, circleID, targetUserID int64) (*model.CircleMember, *model.CircleMember, bool) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if int64(userID) == targetUserID {
+		response.BadRequest(c, "You cannot manage yourself")
+		return nil, nil, false
+	}
+
+	// 检查管理员权限
+	operator, ok := requireCircleAdmin(c, circleID, int64(userID))
+	if !ok {
+		return nil, nil, false
+	}
+
+	target, err := model.GetMember(pgsql.DB, circleID, targetUserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Member not found")
+			return nil, nil, false
+		}
+		response.InternalError(c, "Failed to get member")
+		return nil, nil, false
+	}
+	if target.Status == model.MemberStatusPending {
+		response.NotFound(c, "Member not found")
+		return nil, nil, false
+	}
+
+	if operator.Role <= target.Role {
+		response.Forbidden(c, "You cannot manage a member with the same or higher role")
+		return nil, nil, false
+	}
+
+	return operator, target, true
+}
+
+// newMemberLog 构建成员管理日志
+func newMemberLog(operator, target *model.CircleMember, action int16, reason string) *model.CircleMemberLog {
+	return &model.CircleMemberLog{
+		CircleID:     target.CircleID,
+		OperatorID:   operator.UserID,
+		TargetUserID: target.UserID,
+		Action:       action,
+		Reason:       strings.TrimSpace(reason),
+	}
+}
+
+// respondManageError 处理成员管理操作的数据库错误
+func respondManageError(c *gin.Context, err error) {
+	if err == gorm.ErrRecordNotFound {
+		// 成员记录在操作期间已被其他管理员修改
+		response.Conflict(c, "The member has been changed by someone else, please refresh and retry")
+		return
+	}
+	logger.Log.Error("Failed to manage member: " + err.Error())
+	response.InternalError(c, "Failed to manage member")
+}
@@ -0,0 +1,347 @@
+package controller
+
+import (
+	"fmt"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PostVO 帖子VO（包含作者信息和当前用户的点赞状态）
+type PostVO struct {
+	ID            int64                `json:"id"`
+	CircleID      int64                `json:"circle_id"`
+	Author        UserBriefVO          `json:"author"`
+	Type          int16                `json:"type"`
+	Title         string               `json:"title"`
+	Summary       string               `json:"summary"`
+	Content       string               `json:"content,omitempty"` // 列表中不返回正文
+	MediaExtra    model.MediaExtraJSON `json:"media_extra"`
+	ViewCount     int                  `json:"view_count"`
+	CommentCount  int                  `json:"comment_count"`
+	LikeCount     int                  `json:"like_count"`
+	CollectCount  int                  `json:"collect_count"`
+	IsPinned      int16                `json:"is_pinned"`
+	IsEssence     int16                `json:"is_essence"`
+	IsLock        int16                `json:"is_lock"`
+	Status        int16                `json:"status"`
+	ReviewRemark  string               `json:"review_remark,omitempty"` // 审核意见，仅作者本人可见
+	IsLiked       bool                 `json:"is_liked"`                // 当前用户是否已点赞
+	CreateTime    time.Time            `json:"create_time"`
+	UpdateTime    time.Time            `json:"update_time"`
+	LastReplyTime *time.Time           `json:"last_reply_time,omitempty"`
+}
+
+// newPostVO 根据帖子构建VO，withContent 为 false 时不返回正文
+func newPostVO(post *model.Post, author UserBriefVO, isLiked bool, withContent bool) PostVO {
+	vo := PostVO{
+		ID:            post.ID,
+		CircleID:      post.CircleID,
+		Author:        author,
+		Type:          post.Type,
+		Title:         post.Title,
+		Summary:       post.Summary,
+		MediaExtra:    post.MediaExtra,
+		ViewCount:     post.ViewCount,
+		CommentCount:  post.CommentCount,
+		LikeCount:     post.LikeCount,
+		CollectCount:  post.CollectCount,
+		IsPinned:      post.IsPinned,
+		IsEssence:     post.IsEssence,
+		IsLock:        post.IsLock,
+		Status:        post.Status,
+		IsLiked:       isLiked,
+		CreateTime:    post.CreateTime,
+		UpdateTime:    post.UpdateTime,
+		LastReplyTime: post.LastReplyTime,
+	}
+	if withContent {
+		vo.Content = post.Content
+	}
+	return vo
+}
+
+// buildPostList 批量查询作者信息和点赞状态，组装帖子列表VO
+func buildPostList(posts []model.Post, viewerID int64) ([]PostVO, error) {
+	postIDs := make([]int64, 0, len(posts))
+	userIDs := make([]int64, 0, len(posts))
+	for _, p := range posts {
+		postIDs = append(postIDs, p.ID)
+		userIDs = append(userIDs, p.UserID)
+	}
+
+	authors, err := loadUserBriefs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	liked, err := model.GetLikedPostIDs(pgsql.DB, viewerID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]PostVO, 0, len(posts))
+	for i := range posts {
+		vo := newPostVO(&posts[i], authors[posts[i].UserID], liked[posts[i].ID], false)
+		if posts[i].UserID == viewerID {
+			vo.ReviewRemark = posts[i].ReviewRemark
+		}
+		list = append(list, vo)
+	}
+	applyPendingLikeCounts(list)
+	return list, nil
+}
+
+// GetCirclePostsRequest 获取圈子帖子列表的请求结构
+type GetCirclePostsRequest struct {
+	CircleID int64 `form:"circle_id" binding:"required,min=1"`
+	Page     int   `form:"page"` // 页码，默认1
+	Size     int   `form:"size"` // 每页数量，默认20
+}
+
+// GetCirclePosts 获取圈子帖子列表（置顶帖在第一页最前）
+// GET /circle/post/list
+func (ctrl *CircleController) GetCirclePosts(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetCirclePostsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	// 检查圈子是否存在以及访问权限
+	circle, err := model.GetCircleByID(pgsql.DB, req.CircleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Circle not found")
+			return
+		}
+		response.InternalError(c, "Failed to check circle")
+		return
+	}
+	if _, ok := requireCircleVisible(c, circle, int64(userID)); !ok {
+		return
+	}
+
+	posts, total, err := model.GetPostsByCircle(pgsql.DB, req.CircleID, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get posts: " + err.Error())
+		response.InternalError(c, "Failed to get posts")
+		return
+	}
+
+	list, err := buildPostList(posts, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build post list: " + err.Error())
+		response.InternalError(c, "Failed to get posts")
+		return
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// PostDetailVO 帖子详情VO（帖子信息 + 当前用户的成员信息）
+type PostDetailVO struct {
+	PostVO
+
+	// 当前用户在帖子所属圈子的成员信息
+	IsJoined     bool  `json:"is_joined"`               // 是否已加入圈子
+	MemberRole   int16 `json:"member_role,omitempty"`   // 角色
+	MemberStatus int16 `json:"member_status,omitempty"` // 成员状态
+
+	// 当前用户的收藏状态
+	CollectFolderID int64 `json:"collect_folder_id,omitempty"` // 所在收藏夹ID，未收藏时不返回
+
+	// 投票帖子的投票详情
+	Poll *PollVO `json:"poll,omitempty"`
+}
+
+// GetPostDetail 获取帖子详情
+// GET /circle/post/detail/:id
+func (ctrl *CircleController) GetPostDetail(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 获取post_id参数
+	postIDStr := c.Param("id")
+	var postID int64
+	if _, err := fmt.Sscanf(postIDStr, "%d", &postID); err != nil || postID <= 0 {
+		response.BadRequest(c, "Invalid post id")
+		return
+	}
+
+	// 1. 查询帖子
+	post, err := model.GetPostByID(pgsql.DB, postID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return
+		}
+		logger.Log.Error("Failed to get post: " + err.Error())
+		response.InternalError(c, "Failed to get post")
+		return
+	}
+
+	// 2. 检查圈子访问权限
+	circle, err := model.GetCircleByID(pgsql.DB, post.CircleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return
+		}
+		response.InternalError(c, "Failed to check circle")
+		return
+	}
+	member, ok := requireCircleVisible(c, circle, int64(userID))
+	if !ok {
+		return
+	}
+
+	// 3. 未发布的帖子（草稿、审核中等）只有作者本人可见
+	isAuthor := post.UserID == int64(userID)
+	if post.Status != model.PostStatusPublished && !isAuthor {
+		response.NotFound(c, "Post not found")
+		return
+	}
+
+	// 4. 查询作者信息和点赞状态
+	authors, err := loadUserBriefs([]int64{post.UserID})
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+	isLiked, err := model.IsPostLiked(pgsql.DB, int64(userID), post.ID)
+	if err != nil {
+		response.InternalError(c, "Failed to check like status")
+		return
+	}
+
+	// 5. 已发布的帖子增加浏览量
+	if post.Status == model.PostStatusPublished {
+		if err := model.IncrementViewCount(pgsql.DB, post.ID); err != nil {
+			// 仅记录日志，不影响主流程
+			logger.Log.Error("Failed to increment view count: " + err.Error())
+		} else {
+			post.ViewCount++
+		}
+	}
+
+	// 6. 组装VO
+	vo := PostDetailVO{
+		PostVO: newPostVO(post, authors[post.UserID], isLiked, true),
+	}
+	if isAuthor {
+		vo.ReviewRemark = post.ReviewRemark
+	}
+	likeCounts := []PostVO{vo.PostVO}
+	applyPendingLikeCounts(likeCounts)
+	vo.LikeCount = likeCounts[0].LikeCount
+	if member != nil {
+		vo.IsJoined = member.Status == model.MemberStatusNormal || member.Status == model.MemberStatusMuted
+		vo.MemberRole = member.Role
+		vo.MemberStatus = member.Status
+	}
+	if collect, err := model.GetPostCollect(pgsql.DB, int64(userID), post.ID); err == nil {
+		vo.CollectFolderID = collect.FolderID
+	} else if err != gorm.ErrRecordNotFound {
+		logger.Log.Error("Failed to get collect status: " + err.Error())
+	}
+	if post.Type == model.PostTypeVote {
+		if poll, err := model.GetPollByPostID(pgsql.DB, post.ID); err == nil {
+			if vo.Poll, err = buildPollVO(poll, int64(userID)); err != nil {
+				logger.Log.Error("Failed to build poll: " + err.Error())
+			}
+		} else if err != gorm.ErrRecordNotFound {
+			logger.Log.Error("Failed to get poll: " + err.Error())
+		}
+	}
+
+	response.Success(c, vo)
+}
+
+// GetUserPostsRequest 获取用户帖子列表的请求结构
+type GetUserPostsRequest struct {
+	UserID int64 `form:"user_id" binding:"omitempty,min=1"` // 用户ID，不传则查询自己的帖子
+	Page   int   `form:"page"`                              // 页码，默认1
+	Size   int   `form:"size"`                              // 每页数量，默认20
+}
+
+// GetUserPosts 获取用户的帖子列表（查询自己时包含草稿和审核中的帖子）
+// GET /circle/post/user
+func (ctrl *CircleController) GetUserPosts(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetUserPostsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	var posts []model.Post
+	var total int64
+	var err error
+	if req.UserID == 0 || req.UserID == int64(userID) {
+		// 自己的帖子，返回所有状态
+		posts, total, err = model.GetPostsByUser(pgsql.DB, int64(userID), page, size)
+	} else {
+		// 他人的帖子，只返回已发布且当前用户有权查看的
+		posts, total, err = model.GetVisiblePostsByUser(pgsql.DB, req.UserID, int64(userID), page, size)
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get user posts: " + err.Error())
+		response.InternalError(c, "Failed to get posts")
+		return
+	}
+
+	list, err := buildPostList(posts, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build post list: " + err.Error())
+		response.InternalError(c, "Failed to get posts")
+		return
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// requireCircleVisible 检查用户是否可以查看圈子内容，返回用户的成员信息（未加入时为 nil）
+// 私密圈子仅对正常或禁言状态的成员可见；如果不可见，会直接返回错误响应给客户端
+func requireCircleVisible(c *gin.Context, circle *model.Circle, userID int64) (*model.CircleMember, bool) {
+	member, err := model.GetMember(pgsql.DB, circle.ID, userID)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			response.InternalError(c, "Failed to check membership")
+			return nil, false
+		}
+		member = nil
+	}
+
+	isMember := member != nil &&
+		(member.Status == model.MemberStatusNormal || member.Status == model.MemberStatusMuted)
+	if circle.JoinType == model.CircleJoinTypePrivate && !isMember {
+		response.Forbidden(c, "This circle is private")
+		return nil, false
+	}
+
+	return member, true
+}
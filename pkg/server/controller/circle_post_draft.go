@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetDraftsRequest 获取草稿列表的请求结构
+type GetDraftsRequest struct {
+	Page int `form:"page"` // 页码，默认1
+	Size int `form:"size"` // 每页数量，默认20
+}
+
+// DraftVO 草稿VO
+type DraftVO struct {
+	ID         int64                `json:"id"`
+	CircleID   int64                `json:"circle_id"`
+	Type       int16                `json:"type"`
+	Title      string               `json:"title"`
+	Summary    string               `json:"summary"`
+	Content    string               `json:"content"`
+	MediaExtra model.MediaExtraJSON `json:"media_extra"`
+	PublishAt  *time.Time           `json:"publish_at,omitempty"` // 定时发布时间
+	CreateTime time.Time            `json:"create_time"`
+	UpdateTime time.Time            `json:"update_time"`
+}
+
+// GetDrafts 获取我的草稿列表
+// GET /circle/draft/list
+func (ctrl *CircleController) GetDrafts(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetDraftsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	drafts, total, err := model.GetDraftsByUser(pgsql.DB, int64(userID), page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get drafts: " + err.Error())
+		response.InternalError(c, "Failed to get drafts")
+		return
+	}
+
+	list := make([]DraftVO, 0, len(drafts))
+	for _, d := range drafts {
+		list = append(list, DraftVO{
+			ID:         d.ID,
+			CircleID:   d.CircleID,
+			Type:       d.Type,
+			Title:      d.Title,
+			Summary:    d.Summary,
+			Content:    d.Content,
+			MediaExtra: d.MediaExtra,
+			PublishAt:  d.PublishAt,
+			CreateTime: d.CreateTime,
+			UpdateTime: d.UpdateTime,
+		})
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// UpdateDraftRequest 更新草稿的请求结构，未传的字段保持不变
+type UpdateDraftRequest struct {
+	PostID         int64                  `json:"post_id" binding:"required,min=1"`
+	Title          *string                `json:"title" binding:"omitempty,max=200"`
+	Summary        *string                `json:"summary" binding:"omitempty,max=500"`
+	Content        *string                `json:"content" binding:"omitempty,max=10000"`
+	Type           *int16                 `json:"type" binding:"omitempty,min=1,max=3"` // 投票帖子的投票与帖子一同创建，不能改为或改出投票类型
+	MediaExtra     map[string]interface{} `json:"media_extra" binding:"omitempty"`
+	PublishAt      *time.Time             `json:"publish_at"`      // 设置定时发布时间
+	CancelSchedule bool                   `json:"cancel_schedule"` // 取消定时发布
+}
+
+// UpdateDraft 更新草稿（仅作者本人）
+// POST /circle/draft/update
+func (ctrl *CircleController) UpdateDraft(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req UpdateDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 收集需要更新的字段
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		updates["title"] = strings.TrimSpace(*req.Title)
+	}
+	if req.Summary != nil {
+		updates["summary"] = strings.TrimSpace(*req.Summary)
+	}
+	if req.Content != nil {
+		updates["content"] = *req.Content
+	}
+	if req.Type != nil {
+		// 投票类型需要投票设置，草稿不能与其他类型互相切换
+		draft, err := model.GetPostByID(pgsql.DB, req.PostID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				response.NotFound(c, "Draft not found")
+				return
+			}
+			response.InternalError(c, "Failed to get draft")
+			return
+		}
+		if draft.UserID != int64(userID) || draft.Status != model.PostStatusDraft {
+			response.NotFound(c, "Draft not found")
+			return
+		}
+		if (*req.Type == model.PostTypeVote) != (draft.Type == model.PostTypeVote) {
+			response.BadRequest(c, "The type cannot be changed to or from vote")
+			return
+		}
+		updates["type"] = *req.Type
+	}
+	if req.MediaExtra != nil {
+		updates["media_extra"] = model.MediaExtraJSON(req.MediaExtra)
+	}
+	switch {
+	case req.CancelSchedule:
+		updates["publish_at"] = nil
+	case req.PublishAt != nil:
+		if !req.PublishAt.After(time.Now()) {
+			response.BadRequest(c, "publish_at must be in the future")
+			return
+		}
+		updates["publish_at"] = *req.PublishAt
+	}
+
+	if len(updates) == 0 {
+		response.BadRequest(c, "Nothing to update")
+		return
+	}
+
+	if err := model.UpdateDraft(pgsql.DB, req.PostID, int64(userID), updates); err != nil {
+		if err == model.ErrPostNotDraft {
+			response.NotFound(c, "Draft not found")
+			return
+		}
+		logger.Log.Error("Failed to update draft: " + err.Error())
+		response.InternalError(c, "Failed to update draft")
+		return
+	}
+
+	response.SuccessWithMessage(c, "草稿已保存", nil)
+}
+
+// PublishDraftRequest 发布草稿的请求结构
+type PublishDraftRequest struct {
+	PostID int64 `json:"post_id" binding:"required,min=1"`
+}
+
+// PublishDraft 立即发布草稿（仅作者本人）
+// POST /circle/draft/publish
+func (ctrl *CircleController) PublishDraft(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req PublishDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 查询草稿
+	post, err := model.GetPostByID(pgsql.DB, req.PostID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Draft not found")
+			return
+		}
+		response.InternalError(c, "Failed to get draft")
+		return
+	}
+	if post.UserID != int64(userID) || post.Status != model.PostStatusDraft {
+		response.NotFound(c, "Draft not found")
+		return
+	}
+	if strings.TrimSpace(post.Title) == "" {
+		response.BadRequest(c, "title is required")
+		return
+	}
+
+	// 2. 检查圈子和发帖权限
+	circle, member, ok := requireCanPost(c, post.CircleID, int64(userID))
+	if !ok {
+		return
+	}
+
+	// 3. 发布草稿（直接发布时会更新圈子的帖子计数）
+	status := model.DecidePublishStatus(circle, member)
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.PublishDraft(tx, post, status)
+	}, event.Post(event.PostCreated, post))
+	if err != nil {
+		if err == model.ErrPostNotDraft {
+			response.Conflict(c, "This draft has already been published")
+			return
+		}
+		logger.Log.Error("Failed to publish draft: " + err.Error())
+		response.InternalError(c, "Failed to publish draft")
+		return
+	}
+
+	if status == model.PostStatusReviewing {
+		response.SuccessWithMessage(c, "发帖成功，等待审核", gin.H{"post_id": post.ID, "status": status})
+		return
+	}
+	response.SuccessWithMessage(c, "发帖成功", gin.H{"post_id": post.ID, "status": status})
+}
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/storage/redis"
+	"interestBar/pkg/server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LikePostRequest 点赞/取消点赞帖子的请求结构
+type LikePostRequest struct {
+	PostID int64 `json:"post_id" binding:"required,min=1"`
+}
+
+// LikePost 点赞帖子（幂等，重复点赞不会重复计数）
+// POST /circle/post/like
+func (ctrl *CircleController) LikePost(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req LikePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查帖子是否可见
+	post, ok := requirePostVisible(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+
+	changed, err := model.LikePost(pgsql.DB, int64(userID), post.ID)
+	if err != nil {
+		logger.Log.Error("Failed to like post: " + err.Error())
+		response.InternalError(c, "Failed to like post")
+		return
+	}
+	if changed {
+		changePostLikeCount(post.ID, 1)
+	}
+
+	response.SuccessWithMessage(c, "点赞成功", nil)
+}
+
+// UnlikePost 取消点赞帖子（幂等）
+// POST /circle/post/unlike
+func (ctrl *CircleController) UnlikePost(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req LikePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	post, err := model.GetPostByID(pgsql.DB, req.PostID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return
+		}
+		response.InternalError(c, "Failed to get post")
+		return
+	}
+
+	changed, err := model.UnlikePost(pgsql.DB, int64(userID), post.ID)
+	if err != nil {
+		logger.Log.Error("Failed to unlike post: " + err.Error())
+		response.InternalError(c, "Failed to unlike post")
+		return
+	}
+	if changed {
+		changePostLikeCount(post.ID, -1)
+	}
+
+	response.SuccessWithMessage(c, "已取消点赞", nil)
+}
+
+// GetLikedPostsRequest 获取我点赞的帖子列表的请求结构
+type GetLikedPostsRequest struct {
+	Page int `form:"page"` // 页码，默认1
+	Size int `form:"size"` // 每页数量，默认20
+}
+
+// GetLikedPosts 获取我点赞过的帖子列表（按点赞时间倒序）
+// GET /circle/post/liked
+func (ctrl *CircleController) GetLikedPosts(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetLikedPostsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	// 已删除、不再公开或所在私密圈子已不可见的帖子不展示，在查询中过滤，保证总数与列表一致
+	posts, total, err := model.GetLikedPostsByUser(pgsql.DB, int64(userID), page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get liked posts: " + err.Error())
+		response.InternalError(c, "Failed to get liked posts")
+		return
+	}
+
+	list, err := buildPostList(posts, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build post list: " + err.Error())
+		response.InternalError(c, "Failed to get posts")
+		return
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// requirePostVisible 检查帖子是否存在、已发布且当前用户有权查看，返回帖子信息
+// 如果不可见，会直接返回错误响应给客户端
+func requirePostVisible(c *gin.Context, postID, userID int64) (*model.Post, bool) {
+	post, err := model.GetPostByID(pgsql.DB, postID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to get post")
+		return nil, false
+	}
+	if post.Status != model.PostStatusPublished {
+		response.NotFound(c, "Post not found")
+		return nil, false
+	}
+
+	circle, err := model.GetCircleByID(pgsql.DB, post.CircleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to check circle")
+		return nil, false
+	}
+	if _, ok := requireCircleVisible(c, circle, userID); !ok {
+		return nil, false
+	}
+
+	return post, true
+}
+
+// changePostLikeCount 累加帖子点赞数增量到 Redis，由后台任务批量写回数据库
+// Redis 不可用时退化为直接更新数据库
+func changePostLikeCount(postID int64, delta int64) {
+	err := redis.IncrCounter(redis.PostLikeCounterKey, postID, delta)
+	if err == nil {
+		return
+	}
+	logger.Log.Warn("Failed to buffer like count, fallback to database: " + err.Error())
+
+	if delta > 0 {
+		err = model.IncrementLikeCount(pgsql.DB, postID)
+	} else {
+		err = model.DecrementLikeCount(pgsql.DB, postID)
+	}
+	if err != nil {
+		logger.Log.Error("Failed to update like count: " + err.Error())
+	}
+}
+
+// applyPendingLikeCounts 把尚未写回数据库的点赞数增量合并到帖子VO中
+func applyPendingLikeCounts(list []PostVO) {
+	postIDs := make([]int64, 0, len(list))
+	for _, vo := range list {
+		postIDs = append(postIDs, vo.ID)
+	}
+
+	deltas, err := redis.GetCounters(redis.PostLikeCounterKey, postIDs)
+	if err != nil {
+		// 仅影响展示的实时性，不影响主流程
+		logger.Log.Warn("Failed to get pending like counts: " + err.Error())
+		return
+	}
+
+	for i := range list {
+		list[i].LikeCount += int(deltas[list[i].ID])
+		if list[i].LikeCount < 0 {
+			list[i].LikeCount = 0
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package controller
+
+import (
+	"fmt"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PostFlagRequest 设置帖子运营标记的请求结构
+type PostFlagRequest struct {
+	PostID int64 `json:"post_id" binding:"required,min=1"`
+	Enable *bool `json:"enable" binding:"required"` // true=设置，false=取消
+}
+
+// PinPost 置顶/取消置顶帖子（仅圈子管理员）
+// POST /circle/post/pin
+func (ctrl *CircleController) PinPost(c *gin.Context) {
+	post, enable, ok := bindPostFlag(c)
+	if !ok {
+		return
+	}
+
+	// 置顶数量限制
+	if enable && post.IsPinned == 0 {
+		count, err := model.CountPinnedPosts(pgsql.DB, post.CircleID)
+		if err != nil {
+			response.InternalError(c, "Failed to count pinned posts")
+			return
+		}
+		if count >= model.MaxPinnedPosts {
+			response.Conflict(c, fmt.Sprintf("A circle can have at most %d pinned posts", model.MaxPinnedPosts))
+			return
+		}
+	}
+
+	if err := model.SetPostPinned(pgsql.DB, post.ID, enable); err != nil {
+		respondModeratePostError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "操作成功", nil)
+}
+
+// EssencePost 加精/取消加精帖子（仅圈子管理员）
+// POST /circle/post/essence
+func (ctrl *CircleController) EssencePost(c *gin.Context) {
+	post, enable, ok := bindPostFlag(c)
+	if !ok {
+		return
+	}
+
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.SetPostEssence(tx, post.ID, enable)
+	}, event.Post(event.PostStatusChanged, post))
+	if err != nil {
+		respondModeratePostError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "操作成功", nil)
+}
+
+// LockPost 锁定/解锁帖子（仅圈子管理员），锁定后禁止编辑和评论
+// POST /circle/post/lock
+func (ctrl *CircleController) LockPost(c *gin.Context) {
+	post, enable, ok := bindPostFlag(c)
+	if !ok {
+		return
+	}
+
+	if err := model.SetPostLock(pgsql.DB, post.ID, enable); err != nil {
+		respondModeratePostError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "操作成功", nil)
+}
+
+// BlockPostRequest 屏蔽帖子的请求结构
+type BlockPostRequest struct {
+	PostID int64  `json:"post_id" binding:"required,min=1"`
+	Enable *bool  `json:"enable" binding:"required"`          // true=屏蔽，false=取消屏蔽
+	Reason string `json:"reason" binding:"omitempty,max=500"` // 屏蔽原因，会通知到作者
+}
+
+// BlockPost 屏蔽/取消屏蔽帖子（仅圈子管理员）
+// POST /circle/post/block
+func (ctrl *CircleController) BlockPost(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req BlockPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查帖子和管理权限
+	post, ok := requirePostModerator(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+
+	if !*req.Enable {
+		err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+			return model.UnblockPost(tx, post.ID)
+		}, event.Post(event.PostStatusChanged, post))
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				response.Conflict(c, "This post is not blocked")
+				return
+			}
+			respondModeratePostError(c, err)
+			return
+		}
+		response.SuccessWithMessage(c, "已取消屏蔽", nil)
+		return
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		response.BadRequest(c, "reason is required")
+		return
+	}
+
+	notification := &model.Notification{
+		UserID:    post.UserID,
+		Type:      model.NotificationTypePost,
+		Title:     "帖子已被屏蔽",
+		Content:   fmt.Sprintf("你的帖子「%s」已被圈子管理员屏蔽，原因：%s", post.Title, reason),
+		CircleID:  post.CircleID,
+		RelatedID: post.ID,
+	}
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.BlockPost(tx, post.ID, notification)
+	}, event.Post(event.PostStatusChanged, post))
+	if err != nil {
+		respondModeratePostError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已屏蔽", nil)
+}
+
+// DeletePostRequest 删除帖子的请求结构
+type DeletePostRequest struct {
+	PostID int64 `json:"post_id" binding:"required,min=1"`
+}
+
+// DeletePost 删除帖子（仅作者本人）
+// POST /circle/post/delete
+func (ctrl *CircleController) DeletePost(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req DeletePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	post, err := model.GetPostByID(pgsql.DB, req.PostID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return
+		}
+		response.InternalError(c, "Failed to get post")
+		return
+	}
+
+	if post.UserID != int64(userID) {
+		response.Forbidden(c, "You can only delete your own posts")
+		return
+	}
+
+	// 删除帖子（会更新圈子的帖子计数）
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.DeletePost(tx, post)
+	}, event.Post(event.PostDeleted, post))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return
+		}
+		logger.Log.Error("Failed to delete post: " + err.Error())
+		response.InternalError(c, "Failed to delete post")
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// GetEssencePosts 获取圈子的精华帖子列表
+// GET /circle/post/essence
+func (ctrl *CircleController) GetEssencePosts(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetCirclePostsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	// 检查圈子是否存在以及访问权限
+	circle, err := model.GetCircleByID(pgsql.DB, req.CircleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Circle not found")
+			return
+		}
+		response.InternalError(c, "Failed to check circle")
+		return
+	}
+	if _, ok := requireCircleVisible(c, circle, int64(userID)); !ok {
+		return
+	}
+
+	posts, total, err := model.GetEssencePostsByCircle(pgsql.DB, req.CircleID, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get essence posts: " + err.Error())
+		response.InternalError(c, "Failed to get posts")
+		return
+	}
+
+	list, err := buildPostList(posts, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build post list: " + err.Error())
+		response.InternalError(c, "Failed to get posts")
+		return
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// bindPostFlag 解析设置运营标记的请求并检查管理权限
+func bindPostFlag(c *gin.Context) (*model.Post, bool, bool) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return nil, false, false
+	}
+
+	// 解析请求参数
+	var req PostFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return nil, false, false
+	}
+
+	post, ok := requirePostModerator(c, req.PostID, int64(userID))
+	if !ok {
+		return nil, false, false
+	}
+
+	return post, *req.Enable, true
+}
+
+// requirePostModerator 检查用户是否为帖子所在圈子的管理员，返回帖子信息
+// 如果不是，会直接返回错误响应给客户端
+func requirePostModerator(c *gin.Context, postID, userID int64) (*model.Post, bool) {
+	post, err := model.GetPostByID(pgsql.DB, postID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to get post")
+		return nil, false
+	}
+
+	if _, ok := requireCircleAdmin(c, post.CircleID, userID); !ok {
+		return nil, false
+	}
+
+	return post, true
+}
+
+// respondModeratePostError 处理帖子管理操作的错误
+func respondModeratePostError(c *gin.Context, err error) {
+	if err == model.ErrPostNotPublished {
+		response.Conflict(c, "Only published posts can be moderated")
+		return
+	}
+	logger.Log.Error("Failed to moderate post: " + err.Error())
+	response.InternalError(c, "Failed to moderate post")
+}
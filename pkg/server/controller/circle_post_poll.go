@@ -0,0 +1,390 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/storage/redis"
+	"interestBar/pkg/server/utils"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreatePollRequest 发布投票帖子时的投票设置
+type CreatePollRequest struct {
+	Options     []string   `json:"options" binding:"required,min=2,max=20,dive,required,max=100"` // 选项内容
+	IsMultiple  bool       `json:"is_multiple"`                                                   // 是否多选
+	MaxChoices  int        `json:"max_choices" binding:"omitempty,min=1"`                         // 多选时最多可选项数，默认不限制
+	IsPublic    bool       `json:"is_public"`                                                     // 是否公开投票人，默认匿名
+	HideResults bool       `json:"hide_results"`                                                  // 是否在截止前隐藏结果
+	CloseTime   *time.Time `json:"close_time"`                                                    // 截止时间，不传则不截止
+}
+
+// buildPoll 校验投票设置并构建投票模型；校验失败时会直接返回错误响应给客户端
+func buildPoll(c *gin.Context, req *CreatePollRequest) (*model.Poll, []model.PollOption, bool) {
+	if req.CloseTime != nil && !req.CloseTime.After(time.Now()) {
+		response.BadRequest(c, "close_time must be in the future")
+		return nil, nil, false
+	}
+
+	// 选项去除首尾空格且不能重复
+	options := make([]model.PollOption, 0, len(req.Options))
+	seen := make(map[string]bool, len(req.Options))
+	for _, o := range req.Options {
+		content := strings.TrimSpace(o)
+		if content == "" || seen[content] {
+			response.BadRequest(c, "Poll options must be non-empty and unique")
+			return nil, nil, false
+		}
+		seen[content] = true
+		options = append(options, model.PollOption{Content: content})
+	}
+
+	poll := &model.Poll{
+		MaxChoices:  1,
+		IsAnonymous: 1,
+		CloseTime:   req.CloseTime,
+	}
+	if req.IsMultiple {
+		poll.IsMultiple = 1
+		poll.MaxChoices = len(options)
+		if req.MaxChoices > 0 && req.MaxChoices < len(options) {
+			poll.MaxChoices = req.MaxChoices
+		}
+	}
+	if req.IsPublic {
+		poll.IsAnonymous = 0
+	}
+	if req.HideResults {
+		poll.HideResults = 1
+	}
+
+	return poll, options, true
+}
+
+// PollOptionVO 投票选项VO
+type PollOptionVO struct {
+	ID        int64    `json:"id"`
+	Content   string   `json:"content"`
+	VoteCount *int     `json:"vote_count,omitempty"` // 得票数，结果隐藏时不返回
+	Percent   *float64 `json:"percent,omitempty"`    // 得票率（占参与人数的百分比，保留一位小数），结果隐藏时不返回
+	IsVoted   bool     `json:"is_voted"`             // 当前用户是否选择了该选项
+}
+
+// PollVO 投票VO
+type PollVO struct {
+	PostID         int64          `json:"post_id"`
+	IsMultiple     bool           `json:"is_multiple"`
+	MaxChoices     int            `json:"max_choices"`
+	IsAnonymous    bool           `json:"is_anonymous"`
+	HideResults    bool           `json:"hide_results"`
+	CloseTime      *time.Time     `json:"close_time,omitempty"`
+	IsClosed       bool           `json:"is_closed"`
+	HasVoted       bool           `json:"has_voted"`       // 当前用户是否已投票
+	ResultsVisible bool           `json:"results_visible"` // 是否可以查看结果
+	VoterCount     *int           `json:"voter_count,omitempty"`
+	Options        []PollOptionVO `json:"options"`
+}
+
+// buildPollVO 查询选项、计数和当前用户的投票，组装投票VO
+// 计数合并了 Redis 中尚未写回数据库的增量；设置了截止前隐藏结果的投票，截止前不返回计数
+func buildPollVO(poll *model.Poll, viewerID int64) (*PollVO, error) {
+	options, err := model.GetPollOptions(pgsql.DB, poll.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	voted := make(map[int64]bool)
+	vote, err := model.GetPollVote(pgsql.DB, poll.PostID, viewerID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if vote != nil {
+		for _, id := range vote.OptionIDs {
+			voted[id] = true
+		}
+	}
+
+	vo := &PollVO{
+		PostID:         poll.PostID,
+		IsMultiple:     poll.IsMultiple == 1,
+		MaxChoices:     poll.MaxChoices,
+		IsAnonymous:    poll.IsAnonymous == 1,
+		HideResults:    poll.HideResults == 1,
+		CloseTime:      poll.CloseTime,
+		IsClosed:       poll.IsClosed(),
+		HasVoted:       vote != nil,
+		ResultsVisible: poll.HideResults == 0 || poll.IsClosed(),
+		Options:        make([]PollOptionVO, 0, len(options)),
+	}
+	for _, o := range options {
+		vo.Options = append(vo.Options, PollOptionVO{
+			ID:      o.ID,
+			Content: o.Content,
+			IsVoted: voted[o.ID],
+		})
+	}
+	if !vo.ResultsVisible {
+		return vo, nil
+	}
+
+	// 合并尚未写回的计数增量
+	optionIDs := make([]int64, 0, len(options))
+	for _, o := range options {
+		optionIDs = append(optionIDs, o.ID)
+	}
+	optionDeltas, err := redis.GetCounters(redis.PollOptionCounterKey, optionIDs)
+	if err != nil {
+		// 仅影响展示的实时性，不影响主流程
+		logger.Log.Warn("Failed to get pending poll counts: " + err.Error())
+		optionDeltas = map[int64]int64{}
+	}
+	voterDeltas, err := redis.GetCounters(redis.PollVoterCounterKey, []int64{poll.PostID})
+	if err != nil {
+		logger.Log.Warn("Failed to get pending poll voter count: " + err.Error())
+		voterDeltas = map[int64]int64{}
+	}
+
+	voterCount := poll.VoterCount + int(voterDeltas[poll.PostID])
+	vo.VoterCount = &voterCount
+	for i, o := range options {
+		count := o.VoteCount + int(optionDeltas[o.ID])
+		percent := 0.0
+		if voterCount > 0 {
+			percent = math.Round(float64(count)*1000/float64(voterCount)) / 10
+		}
+		vo.Options[i].VoteCount = &count
+		vo.Options[i].Percent = &percent
+	}
+
+	return vo, nil
+}
+
+// VotePollRequest 投票的请求结构
+type VotePollRequest struct {
+	PostID    int64   `json:"post_id" binding:"required,min=1"`
+	OptionIDs []int64 `json:"option_ids" binding:"required,min=1"` // 选中的选项ID，单选时只能传一个
+}
+
+// VotePoll 参与投票（每人只能投一次，投票后不可修改）
+// POST /circle/poll/vote
+func (ctrl *CircleController) VotePoll(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req VotePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 检查帖子和投票
+	poll, ok := requirePollVisible(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+	if poll.IsClosed() {
+		response.Conflict(c, "This poll is closed")
+		return
+	}
+
+	// 2. 校验选项：不能重复、必须属于该投票、数量不能超过上限
+	if len(req.OptionIDs) > poll.MaxChoices {
+		response.BadRequest(c, "Too many options selected")
+		return
+	}
+	options, err := model.GetPollOptions(pgsql.DB, poll.PostID)
+	if err != nil {
+		response.InternalError(c, "Failed to get poll options")
+		return
+	}
+	valid := make(map[int64]bool, len(options))
+	for _, o := range options {
+		valid[o.ID] = true
+	}
+	selected := make(map[int64]bool, len(req.OptionIDs))
+	for _, id := range req.OptionIDs {
+		if !valid[id] || selected[id] {
+			response.BadRequest(c, "Invalid option selected")
+			return
+		}
+		selected[id] = true
+	}
+
+	// 3. 记录投票并更新计数，计数降级写入数据库时与投票记录同一事务提交
+	err = pgsql.DB.Transaction(func(tx *gorm.DB) error {
+		if err := model.CastPollVote(tx, poll, int64(userID), req.OptionIDs); err != nil {
+			return err
+		}
+		return changePollVoteCount(tx, poll.PostID, req.OptionIDs)
+	})
+	if err != nil {
+		switch err {
+		case model.ErrPollAlreadyVoted:
+			response.Conflict(c, "You have already voted")
+		case model.ErrPollClosed:
+			response.Conflict(c, "This poll is closed")
+		default:
+			logger.Log.Error("Failed to vote: " + err.Error())
+			response.InternalError(c, "Failed to vote")
+		}
+		return
+	}
+
+	vo, err := buildPollVO(poll, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build poll: " + err.Error())
+		response.InternalError(c, "Failed to get poll")
+		return
+	}
+
+	response.SuccessWithMessage(c, "投票成功", vo)
+}
+
+// GetPollRequest 获取投票结果的请求结构
+type GetPollRequest struct {
+	PostID int64 `form:"post_id" binding:"required,min=1"`
+}
+
+// GetPoll 获取投票详情和结果（得票数、得票率）
+// GET /circle/poll/detail
+func (ctrl *CircleController) GetPoll(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetPollRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	poll, ok := requirePollVisible(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+
+	vo, err := buildPollVO(poll, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build poll: " + err.Error())
+		response.InternalError(c, "Failed to get poll")
+		return
+	}
+
+	response.Success(c, vo)
+}
+
+// GetPollVotersRequest 获取选项投票人列表的请求结构
+type GetPollVotersRequest struct {
+	PostID   int64 `form:"post_id" binding:"required,min=1"`
+	OptionID int64 `form:"option_id" binding:"required,min=1"`
+	Page     int   `form:"page"` // 页码，默认1
+	Size     int   `form:"size"` // 每页数量，默认20
+}
+
+// PollVoterVO 投票人VO
+type PollVoterVO struct {
+	User    UserBriefVO `json:"user"`
+	VotedAt time.Time   `json:"voted_at"` // 投票时间
+}
+
+// GetPollVoters 获取选择了某个选项的投票人列表（仅公开投票，且结果可见时）
+// GET /circle/poll/voters
+func (ctrl *CircleController) GetPollVoters(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetPollVotersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	poll, ok := requirePollVisible(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+	if poll.IsAnonymous == 1 {
+		response.Forbidden(c, "This poll is anonymous")
+		return
+	}
+	if poll.HideResults == 1 && !poll.IsClosed() {
+		response.Forbidden(c, "Results are hidden until the poll closes")
+		return
+	}
+
+	votes, total, err := model.GetPollOptionVoters(pgsql.DB, poll.PostID, req.OptionID, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get poll voters: " + err.Error())
+		response.InternalError(c, "Failed to get voters")
+		return
+	}
+
+	userIDs := make([]int64, 0, len(votes))
+	for _, v := range votes {
+		userIDs = append(userIDs, v.UserID)
+	}
+	users, err := loadUserBriefs(userIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	list := make([]PollVoterVO, 0, len(votes))
+	for _, v := range votes {
+		list = append(list, PollVoterVO{
+			User:    users[v.UserID],
+			VotedAt: v.CreateTime,
+		})
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// requirePollVisible 检查帖子可见且带有投票，返回投票设置
+// 如果不可见或不是投票帖子，会直接返回错误响应给客户端
+func requirePollVisible(c *gin.Context, postID, userID int64) (*model.Poll, bool) {
+	post, ok := requirePostVisible(c, postID, userID)
+	if !ok {
+		return nil, false
+	}
+
+	poll, err := model.GetPollByPostID(pgsql.DB, post.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Poll not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to get poll")
+		return nil, false
+	}
+
+	return poll, true
+}
+
+// changePollVoteCount 累加投票计数，在记录投票的事务中调用
+// 优先累加到 Redis 由后台任务批量写回，避免热门投票的选项行成为热点；Redis 不可用时在同一事务中直接更新数据库
+func changePollVoteCount(tx *gorm.DB, postID int64, optionIDs []int64) error {
+	if err := redis.IncrPollVoteCounters(postID, optionIDs); err != nil {
+		logger.Log.Warn("Failed to buffer poll vote count, fallback to database: " + err.Error())
+		return model.IncrementPollVoteCount(tx, postID, optionIDs)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"fmt"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetReviewQueueRequest 获取待审核帖子列表的请求结构
+type GetReviewQueueRequest struct {
+	CircleID int64 `form:"circle_id" binding:"omitempty,min=1"` // 按圈子筛选，不传则查询我管理的全部圈子
+	Page     int   `form:"page"`                                // 页码，默认1
+	Size     int   `form:"size"`                                // 每页数量，默认20
+}
+
+// GetReviewQueue 获取待审核的帖子列表
+// 圈主/管理员只能看到自己管理的圈子，平台管理员可以看到全部圈子
+// GET /circle/review/list
+func (ctrl *CircleController) GetReviewQueue(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetReviewQueueRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	isPlatformAdmin, err := model.IsPlatformAdmin(pgsql.DB, int64(userID))
+	if err != nil {
+		response.InternalError(c, "Failed to check permission")
+		return
+	}
+
+	// 确定查询范围，nil 表示全部圈子
+	var circleIDs []int64
+	switch {
+	case req.CircleID > 0 && isPlatformAdmin:
+		circleIDs = []int64{req.CircleID}
+	case req.CircleID > 0:
+		if _, ok := requireCircleAdmin(c, req.CircleID, int64(userID)); !ok {
+			return
+		}
+		circleIDs = []int64{req.CircleID}
+	case !isPlatformAdmin:
+		circleIDs, err = model.GetAdminCircleIDs(pgsql.DB, int64(userID))
+		if err != nil {
+			response.InternalError(c, "Failed to get managed circles")
+			return
+		}
+		if circleIDs == nil {
+			circleIDs = []int64{}
+		}
+	}
+
+	posts, total, err := model.GetReviewingPosts(pgsql.DB, circleIDs, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get reviewing posts: " + err.Error())
+		response.InternalError(c, "Failed to get reviewing posts")
+		return
+	}
+
+	// 审核时需要查看正文
+	userIDs := make([]int64, 0, len(posts))
+	for _, p := range posts {
+		userIDs = append(userIDs, p.UserID)
+	}
+	authors, err := loadUserBriefs(userIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	list := make([]PostVO, 0, len(posts))
+	for i := range posts {
+		list = append(list, newPostVO(&posts[i], authors[posts[i].UserID], false, true))
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// ApprovePostRequest 审核通过帖子的请求结构
+type ApprovePostRequest struct {
+	PostID int64 `json:"post_id" binding:"required,min=1"`
+}
+
+// ApprovePost 审核通过帖子
+// POST /circle/review/approve
+func (ctrl *CircleController) ApprovePost(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req ApprovePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	post, ok := requirePostReviewer(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+
+	notification := &model.Notification{
+		UserID:    post.UserID,
+		Type:      model.NotificationTypePost,
+		Title:     "帖子审核通过",
+		Content:   fmt.Sprintf("你的帖子「%s」已通过审核", post.Title),
+		CircleID:  post.CircleID,
+		RelatedID: post.ID,
+	}
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.ReviewPost(tx, post.ID, model.PostStatusPublished, "", notification)
+	}, event.Post(event.PostStatusChanged, post))
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "审核通过", nil)
+}
+
+// RejectPostRequest 驳回帖子的请求结构
+type RejectPostRequest struct {
+	PostID int64  `json:"post_id" binding:"required,min=1"`
+	Reason string `json:"reason" binding:"required,min=1,max=500"` // 驳回原因，作者可见
+}
+
+// RejectPost 审核驳回帖子
+// POST /circle/review/reject
+func (ctrl *CircleController) RejectPost(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req RejectPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		response.BadRequest(c, "reason is required")
+		return
+	}
+
+	post, ok := requirePostReviewer(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+
+	notification := &model.Notification{
+		UserID:    post.UserID,
+		Type:      model.NotificationTypePost,
+		Title:     "帖子审核未通过",
+		Content:   fmt.Sprintf("你的帖子「%s」未通过审核，原因：%s", post.Title, reason),
+		CircleID:  post.CircleID,
+		RelatedID: post.ID,
+	}
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.ReviewPost(tx, post.ID, model.PostStatusRejected, reason, notification)
+	}, event.Post(event.PostStatusChanged, post))
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已驳回", nil)
+}
+
+// requirePostReviewer 检查用户是否可以审核帖子（平台管理员或帖子所在圈子的管理员），返回帖子信息
+// 如果不可以，会直接返回错误响应给客户端
+func requirePostReviewer(c *gin.Context, postID, userID int64) (*model.Post, bool) {
+	post, err := model.GetPostByID(pgsql.DB, postID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to get post")
+		return nil, false
+	}
+
+	isPlatformAdmin, err := model.IsPlatformAdmin(pgsql.DB, userID)
+	if err != nil {
+		response.InternalError(c, "Failed to check permission")
+		return nil, false
+	}
+	if isPlatformAdmin {
+		return post, true
+	}
+
+	if _, ok := requireCircleAdmin(c, post.CircleID, userID); !ok {
+		return nil, false
+	}
+
+	return post, true
+}
+
+// respondReviewError 处理审核操作的错误
+func respondReviewError(c *gin.Context, err error) {
+	if err == model.ErrPostNotReviewing {
+		response.Conflict(c, "This post is not under review")
+		return
+	}
+	logger.Log.Error("Failed to review post: " + err.Error())
+	response.InternalError(c, "Failed to review post")
+}
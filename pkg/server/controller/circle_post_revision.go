@@ -0,0 +1,296 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// EditPostRequest 编辑帖子的请求结构，未传的字段保持不变
+type EditPostRequest struct {
+	PostID     int64                  `json:"post_id" binding:"required,min=1"`
+	Title      *string                `json:"title" binding:"omitempty,max=200"`
+	Summary    *string                `json:"summary" binding:"omitempty,max=500"`
+	Content    *string                `json:"content" binding:"omitempty,max=10000"`
+	MediaExtra map[string]interface{} `json:"media_extra" binding:"omitempty"`
+	Reason     string                 `json:"reason" binding:"omitempty,max=200"` // 编辑理由，管理员编辑他人帖子时建议填写
+}
+
+// EditPost 编辑帖子（作者本人或圈子管理员），每次编辑都会保存修订记录
+// POST /circle/post/edit
+func (ctrl *CircleController) EditPost(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req EditPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 检查帖子和编辑权限
+	post, ok := requirePostEditor(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+
+	// 2. 合并编辑内容
+	edit := model.PostEdit{
+		Title:      post.Title,
+		Summary:    post.Summary,
+		Content:    post.Content,
+		MediaExtra: post.MediaExtra,
+		EditorID:   int64(userID),
+		Reason:     strings.TrimSpace(req.Reason),
+	}
+	changed := false
+	if req.Title != nil {
+		edit.Title = strings.TrimSpace(*req.Title)
+		changed = changed || edit.Title != post.Title
+	}
+	if req.Summary != nil {
+		edit.Summary = strings.TrimSpace(*req.Summary)
+		changed = changed || edit.Summary != post.Summary
+	}
+	if req.Content != nil {
+		edit.Content = *req.Content
+		changed = changed || edit.Content != post.Content
+	}
+	if req.MediaExtra != nil {
+		edit.MediaExtra = req.MediaExtra
+		changed = true
+	}
+	if edit.MediaExtra == nil {
+		edit.MediaExtra = make(model.MediaExtraJSON)
+	}
+
+	// 非草稿帖子的标题不能为空
+	if post.Status != model.PostStatusDraft && edit.Title == "" {
+		response.BadRequest(c, "title is required")
+		return
+	}
+	if !changed {
+		response.BadRequest(c, "Nothing to update")
+		return
+	}
+
+	// 3. 保存修订记录并更新帖子
+	var revision *model.PostRevision
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		var err error
+		revision, err = model.EditPost(tx, post.ID, &edit)
+		return err
+	}, event.Post(event.PostUpdated, post))
+	if err != nil {
+		respondEditPostError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "编辑成功", gin.H{"version": revision.Version})
+}
+
+// GetPostRevisionsRequest 获取帖子修订历史的请求结构
+type GetPostRevisionsRequest struct {
+	PostID int64 `form:"post_id" binding:"required,min=1"`
+	Page   int   `form:"page"` // 页码，默认1
+	Size   int   `form:"size"` // 每页数量，默认20
+}
+
+// PostRevisionVO 帖子修订记录VO
+type PostRevisionVO struct {
+	Version      int                  `json:"version"`
+	Editor       UserBriefVO          `json:"editor"`
+	Title        string               `json:"title"`
+	Summary      string               `json:"summary"`
+	Content      string               `json:"content"`
+	MediaExtra   model.MediaExtraJSON `json:"media_extra"`
+	Reason       string               `json:"reason,omitempty"`
+	RestoredFrom int                  `json:"restored_from,omitempty"` // 由哪个版本恢复而来
+	CreateTime   time.Time            `json:"create_time"`
+}
+
+// GetPostRevisions 获取帖子的修订历史（作者本人或圈子管理员）
+// GET /circle/post/revisions
+func (ctrl *CircleController) GetPostRevisions(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetPostRevisionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	// 检查帖子和查看权限
+	if _, ok := requirePostEditor(c, req.PostID, int64(userID)); !ok {
+		return
+	}
+
+	revisions, total, err := model.GetPostRevisions(pgsql.DB, req.PostID, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get post revisions: " + err.Error())
+		response.InternalError(c, "Failed to get post revisions")
+		return
+	}
+
+	// 批量查询编辑人信息
+	editorIDs := make([]int64, 0, len(revisions))
+	for _, r := range revisions {
+		editorIDs = append(editorIDs, r.EditorID)
+	}
+	editors, err := loadUserBriefs(editorIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	list := make([]PostRevisionVO, 0, len(revisions))
+	for _, r := range revisions {
+		list = append(list, PostRevisionVO{
+			Version:      r.Version,
+			Editor:       editors[r.EditorID],
+			Title:        r.Title,
+			Summary:      r.Summary,
+			Content:      r.Content,
+			MediaExtra:   r.MediaExtra,
+			Reason:       r.Reason,
+			RestoredFrom: r.RestoredFrom,
+			CreateTime:   r.CreateTime,
+		})
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// RestorePostRevisionRequest 恢复帖子版本的请求结构
+type RestorePostRevisionRequest struct {
+	PostID  int64  `json:"post_id" binding:"required,min=1"`
+	Version int    `json:"version" binding:"required,min=1"`
+	Reason  string `json:"reason" binding:"omitempty,max=200"`
+}
+
+// RestorePostRevision 将帖子恢复到指定版本（作者本人或圈子管理员）
+// 恢复操作本身也会生成一条新的修订记录，历史记录不会被覆盖
+// POST /circle/post/revision/restore
+func (ctrl *CircleController) RestorePostRevision(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req RestorePostRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 检查帖子和编辑权限
+	post, ok := requirePostEditor(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+
+	// 2. 查询要恢复的版本
+	target, err := model.GetPostRevision(pgsql.DB, post.ID, req.Version)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Revision not found")
+			return
+		}
+		response.InternalError(c, "Failed to get revision")
+		return
+	}
+
+	// 3. 以目标版本的快照作为一次新的编辑
+	edit := model.PostEdit{
+		Title:        target.Title,
+		Summary:      target.Summary,
+		Content:      target.Content,
+		MediaExtra:   target.MediaExtra,
+		EditorID:     int64(userID),
+		Reason:       strings.TrimSpace(req.Reason),
+		RestoredFrom: target.Version,
+	}
+	if edit.MediaExtra == nil {
+		edit.MediaExtra = make(model.MediaExtraJSON)
+	}
+
+	var revision *model.PostRevision
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		var err error
+		revision, err = model.EditPost(tx, post.ID, &edit)
+		return err
+	}, event.Post(event.PostUpdated, post))
+	if err != nil {
+		respondEditPostError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已恢复到指定版本", gin.H{"version": revision.Version})
+}
+
+// requirePostEditor 检查用户是否可以编辑帖子（作者本人或帖子所在圈子的管理员），返回帖子信息
+// 如果不可以，会直接返回错误响应给客户端
+func requirePostEditor(c *gin.Context, postID, userID int64) (*model.Post, bool) {
+	post, err := model.GetPostByID(pgsql.DB, postID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Post not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to get post")
+		return nil, false
+	}
+
+	member, err := model.GetMember(pgsql.DB, post.CircleID, userID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		response.InternalError(c, "Failed to check membership")
+		return nil, false
+	}
+
+	isAdmin := member != nil && member.Status == model.MemberStatusNormal && member.Role >= model.MemberRoleAdmin
+	if post.UserID != userID && !isAdmin {
+		response.Forbidden(c, "You cannot edit this post")
+		return nil, false
+	}
+
+	// 被拉黑的作者不能再编辑自己在该圈子的帖子
+	if !isAdmin && member != nil && member.Status == model.MemberStatusBanned {
+		response.Forbidden(c, "You have been banned from this circle")
+		return nil, false
+	}
+
+	return post, true
+}
+
+// respondEditPostError 处理编辑帖子的错误
+func respondEditPostError(c *gin.Context, err error) {
+	switch err {
+	case gorm.ErrRecordNotFound:
+		response.NotFound(c, "Post not found")
+	case model.ErrPostLocked:
+		response.Forbidden(c, "This post is locked and cannot be edited")
+	default:
+		logger.Log.Error("Failed to edit post: " + err.Error())
+		response.InternalError(c, "Failed to edit post")
+	}
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"encoding/json"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/storage/elasticsearch"
+	"interestBar/pkg/server/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchPostsRequest 搜索帖子的请求结构
+type SearchPostsRequest struct {
+	Keyword     string `form:"keyword"`                                             // 搜索关键字，匹配标题、摘要和正文
+	CircleID    int64  `form:"circle_id" binding:"omitempty,min=1"`                 // 按圈子筛选
+	Type        int16  `form:"type" binding:"omitempty,oneof=1 2 3"`                // 按帖子类型筛选
+	Essence     bool   `form:"essence"`                                             // 只看精华帖
+	StartTime   string `form:"start_time"`                                          // 发布时间下限(RFC3339)
+	EndTime     string `form:"end_time"`                                            // 发布时间上限(RFC3339)
+	Sort        string `form:"sort" binding:"omitempty,oneof=relevance newest hot"` // 排序方式，有关键字时默认 relevance，否则 newest
+	Size        int    `form:"size"`                                                // 每页数量，默认20
+	SearchAfter string `form:"search_after"`                                        // 上一页返回的search_after值（JSON字符串）
+}
+
+// PostSearchVO 帖子搜索结果VO
+type PostSearchVO struct {
+	elasticsearch.PostDocument
+	Author UserBriefVO `json:"author"`
+}
+
+// SearchPosts 搜索帖子（支持按圈子、类型、精华、发布时间筛选，返回高亮片段）
+// GET /circle/post/search
+func (ctrl *CircleController) SearchPosts(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req SearchPostsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 校验时间范围
+	for _, t := range []string{req.StartTime, req.EndTime} {
+		if t == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, t); err != nil {
+			response.BadRequest(c, "Invalid time range, expected RFC3339 format")
+			return
+		}
+	}
+
+	// 解析 search_after 参数
+	var searchAfter []interface{}
+	if req.SearchAfter != "" {
+		if err := json.Unmarshal([]byte(req.SearchAfter), &searchAfter); err != nil {
+			response.BadRequest(c, "Invalid search_after parameter")
+			return
+		}
+	}
+
+	// 未加入的私密圈子中的帖子不返回
+	hiddenCircleIDs, err := model.GetHiddenCircleIDs(pgsql.DB, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to get hidden circles: " + err.Error())
+		response.InternalError(c, "Failed to search posts")
+		return
+	}
+
+	result, err := elasticsearch.SearchPosts(elasticsearch.PostSearchQuery{
+		Keyword:         strings.TrimSpace(req.Keyword),
+		CircleID:        req.CircleID,
+		Type:            req.Type,
+		EssenceOnly:     req.Essence,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		HiddenCircleIDs: hiddenCircleIDs,
+		Sort:            req.Sort,
+		Size:            req.Size,
+		SearchAfter:     searchAfter,
+	})
+	if err != nil {
+		logger.Log.Error("Failed to search posts: " + err.Error())
+		response.InternalError(c, "Failed to search posts")
+		return
+	}
+
+	// 批量查询作者信息
+	userIDs := make([]int64, 0, len(result.Posts))
+	for _, p := range result.Posts {
+		userIDs = append(userIDs, p.UserID)
+	}
+	authors, err := loadUserBriefs(userIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	list := make([]PostSearchVO, 0, len(result.Posts))
+	for _, p := range result.Posts {
+		list = append(list, PostSearchVO{PostDocument: p, Author: authors[p.UserID]})
+	}
+
+	// 将 search_after 转换为 JSON 字符串返回
+	var searchAfterJSON string
+	if result.SearchAfter != nil {
+		if bytes, err := json.Marshal(result.SearchAfter); err == nil {
+			searchAfterJSON = string(bytes)
+		}
+	}
+
+	response.Success(c, map[string]interface{}{
+		"posts":        list,
+		"total":        result.Total,
+		"size":         result.Size,
+		"search_after": searchAfterJSON,
+	})
+}
@@ -0,0 +1,395 @@
+package controller
+
+import (
+	"fmt"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateTransferRequest 发起圈主转让的请求结构
+type CreateTransferRequest struct {
+	CircleID int64 `json:"circle_id" binding:"required,min=1"`
+	UserID   int64 `json:"user_id" binding:"required,min=1"` // 接收转让的成员ID
+}
+
+// CreateTransfer 发起圈主转让（仅圈主，需接收人确认后生效）
+// POST /circle/transfer/create
+func (ctrl *CircleController) CreateTransfer(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	if req.UserID == int64(userID) {
+		response.BadRequest(c, "You cannot transfer the circle to yourself")
+		return
+	}
+
+	// 1. 检查圈主权限
+	circle, ok := requireCircleOwner(c, req.CircleID, int64(userID))
+	if !ok {
+		return
+	}
+
+	// 2. 接收人必须是正常状态的成员
+	target, err := model.GetMember(pgsql.DB, req.CircleID, req.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Member not found")
+			return
+		}
+		response.InternalError(c, "Failed to get member")
+		return
+	}
+	if target.Status != model.MemberStatusNormal {
+		response.Forbidden(c, "Only members in normal status can receive the circle")
+		return
+	}
+
+	// 3. 创建转让记录并通知接收人
+	transfer := model.CircleTransfer{
+		CircleID:   req.CircleID,
+		FromUserID: int64(userID),
+		ToUserID:   req.UserID,
+		Status:     model.CircleTransferStatusPending,
+		ExpireTime: time.Now().Add(model.CircleTransferExpire),
+	}
+	notification := model.Notification{
+		UserID:   req.UserID,
+		Type:     model.NotificationTypeCircle,
+		Title:    "圈主转让邀请",
+		Content:  fmt.Sprintf("圈主希望将圈子「%s」转让给你，请在 %s 前确认", circle.Name, transfer.ExpireTime.Format("2006-01-02 15:04")),
+		CircleID: req.CircleID,
+	}
+
+	if err := model.CreateTransfer(pgsql.DB, &transfer, &notification); err != nil {
+		logger.Log.Error("Failed to create transfer: " + err.Error())
+		response.InternalError(c, "Failed to create transfer")
+		return
+	}
+
+	response.SuccessWithMessage(c, "已发起转让，等待对方确认", gin.H{
+		"transfer_id": transfer.ID,
+		"expire_time": transfer.ExpireTime,
+	})
+}
+
+// CancelTransferRequest 取消圈主转让的请求结构
+type CancelTransferRequest struct {
+	CircleID int64 `json:"circle_id" binding:"required,min=1"`
+}
+
+// CancelTransfer 取消待确认的圈主转让（仅圈主）
+// POST /circle/transfer/cancel
+func (ctrl *CircleController) CancelTransfer(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req CancelTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查圈主权限
+	if _, ok := requireCircleOwner(c, req.CircleID, int64(userID)); !ok {
+		return
+	}
+
+	transfer, err := model.GetPendingTransferByCircle(pgsql.DB, req.CircleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "No pending transfer")
+			return
+		}
+		response.InternalError(c, "Failed to get transfer")
+		return
+	}
+
+	if err := model.CloseTransfer(pgsql.DB, transfer.ID, model.CircleTransferStatusCanceled); err != nil {
+		respondTransferError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已取消转让", nil)
+}
+
+// IncomingTransferVO 转让给我的圈子VO
+type IncomingTransferVO struct {
+	TransferID int64       `json:"transfer_id"`
+	CircleID   int64       `json:"circle_id"`
+	CircleName string      `json:"circle_name"`
+	FromUser   UserBriefVO `json:"from_user"`
+	ExpireTime time.Time   `json:"expire_time"`
+	CreateTime time.Time   `json:"create_time"`
+}
+
+// GetIncomingTransfers 获取转让给我且待确认的圈子
+// GET /circle/transfer/incoming
+func (ctrl *CircleController) GetIncomingTransfers(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	transfers, err := model.GetPendingTransfersByUser(pgsql.DB, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to get transfers: " + err.Error())
+		response.InternalError(c, "Failed to get transfers")
+		return
+	}
+
+	// 批量查询圈子和发起人信息
+	circleIDs := make([]int64, 0, len(transfers))
+	userIDs := make([]int64, 0, len(transfers))
+	for _, t := range transfers {
+		circleIDs = append(circleIDs, t.CircleID)
+		userIDs = append(userIDs, t.FromUserID)
+	}
+	circles, err := model.GetCirclesByIDs(pgsql.DB, circleIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get circle info")
+		return
+	}
+	circleNames := make(map[int64]string, len(circles))
+	for _, ci := range circles {
+		circleNames[ci.ID] = ci.Name
+	}
+	users, err := loadUserBriefs(userIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	list := make([]IncomingTransferVO, 0, len(transfers))
+	for _, t := range transfers {
+		name, exists := circleNames[t.CircleID]
+		if !exists {
+			// 圈子已解散
+			continue
+		}
+		list = append(list, IncomingTransferVO{
+			TransferID: t.ID,
+			CircleID:   t.CircleID,
+			CircleName: name,
+			FromUser:   users[t.FromUserID],
+			ExpireTime: t.ExpireTime,
+			CreateTime: t.CreateTime,
+		})
+	}
+
+	response.Success(c, list)
+}
+
+// HandleTransferRequest 处理圈主转让的请求结构
+type HandleTransferRequest struct {
+	TransferID int64 `json:"transfer_id" binding:"required,min=1"`
+}
+
+// AcceptTransfer 接受圈主转让（仅接收人）
+// POST /circle/transfer/accept
+func (ctrl *CircleController) AcceptTransfer(c *gin.Context) {
+	transfer, ok := loadIncomingTransfer(c)
+	if !ok {
+		return
+	}
+
+	circle, err := model.GetCircleByID(pgsql.DB, transfer.CircleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Circle not found")
+			return
+		}
+		response.InternalError(c, "Failed to check circle")
+		return
+	}
+
+	log := &model.CircleMemberLog{
+		CircleID:     transfer.CircleID,
+		OperatorID:   transfer.FromUserID,
+		TargetUserID: transfer.ToUserID,
+		Action:       model.MemberActionTransfer,
+		Reason:       "圈主转让",
+	}
+	notification := &model.Notification{
+		UserID:    transfer.FromUserID,
+		Type:      model.NotificationTypeCircle,
+		Title:     "圈主转让已完成",
+		Content:   fmt.Sprintf("圈子「%s」已成功转让，你已成为该圈子的管理员", circle.Name),
+		CircleID:  transfer.CircleID,
+		RelatedID: transfer.ID,
+	}
+
+	if err := model.AcceptTransfer(pgsql.DB, transfer, log, notification); err != nil {
+		respondTransferError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "你已成为圈主", nil)
+}
+
+// DeclineTransfer 拒绝圈主转让（仅接收人）
+// POST /circle/transfer/decline
+func (ctrl *CircleController) DeclineTransfer(c *gin.Context) {
+	transfer, ok := loadIncomingTransfer(c)
+	if !ok {
+		return
+	}
+
+	if err := model.CloseTransfer(pgsql.DB, transfer.ID, model.CircleTransferStatusDeclined); err != nil {
+		respondTransferError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "已拒绝转让", nil)
+}
+
+// DissolveCircleRequest 解散圈子的请求结构
+type DissolveCircleRequest struct {
+	CircleID int64  `json:"circle_id" binding:"required,min=1"`
+	Reason   string `json:"reason" binding:"omitempty,max=500"` // 解散原因，会通知到全体成员
+}
+
+// DissolveCircle 解散圈子（仅圈主）
+// POST /circle/dissolve
+func (ctrl *CircleController) DissolveCircle(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req DissolveCircleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查圈主权限
+	circle, ok := requireCircleOwner(c, req.CircleID, int64(userID))
+	if !ok {
+		return
+	}
+
+	content := fmt.Sprintf("你加入的圈子「%s」已被圈主解散", circle.Name)
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		content += "，原因：" + reason
+	}
+	notification := &model.Notification{
+		Type:    model.NotificationTypeCircle,
+		Title:   "圈子已解散",
+		Content: content,
+	}
+
+	// 同一事务写入圈子解散事件（从 Elasticsearch 中删除圈子）
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.DissolveCircle(tx, circle.ID, notification)
+	}, event.Circle(event.CircleDeleted, circle))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Circle not found")
+			return
+		}
+		logger.Log.Error("Failed to dissolve circle: " + err.Error())
+		response.InternalError(c, "Failed to dissolve circle")
+		return
+	}
+
+	response.SuccessWithMessage(c, "圈子已解散", nil)
+}
+
+// requireCircleOwner 检查用户是否为圈主，返回圈子信息
+// 如果不是，会直接返回错误响应给客户端
+func requireCircleOwner(c *gin.Context, circleID, userID int64) (*model.Circle, bool) {
+	circle, err := model.GetCircleByID(pgsql.DB, circleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Circle not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to check circle")
+		return nil, false
+	}
+
+	isOwner, err := model.IsOwner(pgsql.DB, circleID, userID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		response.InternalError(c, "Failed to check membership")
+		return nil, false
+	}
+	if !isOwner {
+		response.Forbidden(c, "Only the owner can perform this action")
+		return nil, false
+	}
+
+	return circle, true
+}
+
+// loadIncomingTransfer 解析请求并加载转让给当前用户的待确认转让
+func loadIncomingTransfer(c *gin.Context) (*model.CircleTransfer, bool) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return nil, false
+	}
+
+	// 解析请求参数
+	var req HandleTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return nil, false
+	}
+
+	transfer, err := model.GetTransferByID(pgsql.DB, req.TransferID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Transfer not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to get transfer")
+		return nil, false
+	}
+
+	if transfer.ToUserID != int64(userID) {
+		response.Forbidden(c, "This transfer is not for you")
+		return nil, false
+	}
+	if transfer.Status != model.CircleTransferStatusPending || !transfer.ExpireTime.After(time.Now()) {
+		response.Conflict(c, "This transfer is no longer available")
+		return nil, false
+	}
+
+	return transfer, true
+}
+
+// respondTransferError 处理圈主转让操作的错误
+func respondTransferError(c *gin.Context, err error) {
+	if err == model.ErrTransferUnavailable {
+		response.Conflict(c, "This transfer is no longer available")
+		return
+	}
+	logger.Log.Error("Failed to handle transfer: " + err.Error())
+	response.InternalError(c, "Failed to handle transfer")
+}
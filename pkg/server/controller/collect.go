@@ -0,0 +1,396 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CollectController 处理收藏夹和帖子收藏相关操作
+type CollectController struct{}
+
+func NewCollectController() *CollectController {
+	return &CollectController{}
+}
+
+// CollectFolderVO 收藏夹VO
+type CollectFolderVO struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsPublic    bool      `json:"is_public"`
+	ItemCount   int       `json:"item_count"`
+	CreateTime  time.Time `json:"create_time"`
+	UpdateTime  time.Time `json:"update_time"`
+}
+
+// newCollectFolderVO 根据收藏夹构建VO
+func newCollectFolderVO(folder *model.CollectFolder) CollectFolderVO {
+	return CollectFolderVO{
+		ID:          folder.ID,
+		UserID:      folder.UserID,
+		Name:        folder.Name,
+		Description: folder.Description,
+		IsPublic:    folder.IsPublic == 1,
+		ItemCount:   folder.ItemCount,
+		CreateTime:  folder.CreateTime,
+		UpdateTime:  folder.UpdateTime,
+	}
+}
+
+// CreateFolderRequest 创建收藏夹的请求结构
+type CreateFolderRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=50"`
+	Description string `json:"description" binding:"omitempty,max=200"`
+	IsPublic    bool   `json:"is_public"` // 是否公开，默认私密
+}
+
+// CreateFolder 创建收藏夹
+// POST /collect/folder/create
+func (ctrl *CollectController) CreateFolder(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req CreateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		response.BadRequest(c, "name is required")
+		return
+	}
+
+	folder := &model.CollectFolder{
+		UserID:      int64(userID),
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if req.IsPublic {
+		folder.IsPublic = 1
+	}
+
+	if err := model.CreateCollectFolder(pgsql.DB, folder); err != nil {
+		respondCollectError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "收藏夹创建成功", newCollectFolderVO(folder))
+}
+
+// UpdateFolderRequest 更新收藏夹的请求结构（只更新传入的字段）
+type UpdateFolderRequest struct {
+	FolderID    int64   `json:"folder_id" binding:"required,min=1"`
+	Name        *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Description *string `json:"description" binding:"omitempty,max=200"`
+	IsPublic    *bool   `json:"is_public"`
+}
+
+// UpdateFolder 更新收藏夹（重命名、修改描述、设置公开或私密）
+// POST /collect/folder/update
+func (ctrl *CollectController) UpdateFolder(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req UpdateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	folder, ok := requireFolderOwner(c, req.FolderID, int64(userID))
+	if !ok {
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			response.BadRequest(c, "name cannot be empty")
+			return
+		}
+		updates["name"] = name
+	}
+	if req.Description != nil {
+		updates["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.IsPublic != nil {
+		isPublic := int16(0)
+		if *req.IsPublic {
+			isPublic = 1
+		}
+		updates["is_public"] = isPublic
+	}
+	if len(updates) == 0 {
+		response.BadRequest(c, "No fields to update")
+		return
+	}
+
+	if err := model.UpdateCollectFolder(pgsql.DB, folder, updates); err != nil {
+		respondCollectError(c, err)
+		return
+	}
+
+	// 重新查询以返回最新数据
+	folder, err := model.GetCollectFolderByID(pgsql.DB, folder.ID)
+	if err != nil {
+		response.InternalError(c, "Failed to get folder")
+		return
+	}
+
+	response.SuccessWithMessage(c, "收藏夹已更新", newCollectFolderVO(folder))
+}
+
+// DeleteFolderRequest 删除收藏夹的请求结构
+type DeleteFolderRequest struct {
+	FolderID int64 `json:"folder_id" binding:"required,min=1"`
+}
+
+// DeleteFolder 删除收藏夹（其中的收藏一并取消）
+// POST /collect/folder/delete
+func (ctrl *CollectController) DeleteFolder(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req DeleteFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	folder, ok := requireFolderOwner(c, req.FolderID, int64(userID))
+	if !ok {
+		return
+	}
+
+	if err := model.DeleteCollectFolder(pgsql.DB, folder.ID); err != nil {
+		respondCollectError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "收藏夹已删除", nil)
+}
+
+// GetFoldersRequest 获取收藏夹列表的请求结构
+type GetFoldersRequest struct {
+	UserID int64 `form:"user_id" binding:"omitempty,min=1"` // 用户ID，不传则查询自己的收藏夹
+}
+
+// GetFolders 获取收藏夹列表（查询他人时只返回公开的收藏夹）
+// GET /collect/folder/list
+func (ctrl *CollectController) GetFolders(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetFoldersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	ownerID := req.UserID
+	if ownerID == 0 {
+		ownerID = int64(userID)
+	}
+
+	folders, err := model.GetCollectFoldersByUser(pgsql.DB, ownerID, ownerID != int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to get folders: " + err.Error())
+		response.InternalError(c, "Failed to get folders")
+		return
+	}
+
+	list := make([]CollectFolderVO, 0, len(folders))
+	for i := range folders {
+		list = append(list, newCollectFolderVO(&folders[i]))
+	}
+
+	response.Success(c, list)
+}
+
+// GetFolderPostsRequest 获取收藏夹中帖子的请求结构
+type GetFolderPostsRequest struct {
+	FolderID int64 `form:"folder_id" binding:"required,min=1"`
+	Page     int   `form:"page"` // 页码，默认1
+	Size     int   `form:"size"` // 每页数量，默认20
+}
+
+// GetFolderPosts 获取收藏夹中的帖子（按收藏时间倒序，私密收藏夹仅本人可见）
+// GET /collect/folder/posts
+func (ctrl *CollectController) GetFolderPosts(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetFolderPostsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	folder, err := model.GetCollectFolderByID(pgsql.DB, req.FolderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Folder not found")
+			return
+		}
+		response.InternalError(c, "Failed to get folder")
+		return
+	}
+	// 私密收藏夹对他人表现为不存在
+	if folder.IsPublic != 1 && folder.UserID != int64(userID) {
+		response.NotFound(c, "Folder not found")
+		return
+	}
+
+	posts, total, err := model.GetCollectedPosts(pgsql.DB, folder.ID, int64(userID), page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get collected posts: " + err.Error())
+		response.InternalError(c, "Failed to get posts")
+		return
+	}
+
+	list, err := buildPostList(posts, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build post list: " + err.Error())
+		response.InternalError(c, "Failed to get posts")
+		return
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// CollectPostRequest 收藏帖子的请求结构
+type CollectPostRequest struct {
+	PostID   int64 `json:"post_id" binding:"required,min=1"`
+	FolderID int64 `json:"folder_id" binding:"required,min=1"`
+}
+
+// CollectPost 收藏帖子到收藏夹（已收藏在其他收藏夹时移动到目标收藏夹）
+// POST /collect/add
+func (ctrl *CollectController) CollectPost(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req CollectPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	folder, ok := requireFolderOwner(c, req.FolderID, int64(userID))
+	if !ok {
+		return
+	}
+	post, ok := requirePostVisible(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+
+	if err := model.CollectPost(pgsql.DB, int64(userID), post.ID, folder.ID); err != nil {
+		respondCollectError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "收藏成功", nil)
+}
+
+// UncollectPostRequest 取消收藏的请求结构
+type UncollectPostRequest struct {
+	PostID int64 `json:"post_id" binding:"required,min=1"`
+}
+
+// UncollectPost 取消收藏帖子（幂等）
+// POST /collect/remove
+func (ctrl *CollectController) UncollectPost(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req UncollectPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	if _, err := model.UncollectPost(pgsql.DB, int64(userID), req.PostID); err != nil {
+		logger.Log.Error("Failed to uncollect post: " + err.Error())
+		response.InternalError(c, "Failed to uncollect post")
+		return
+	}
+
+	response.SuccessWithMessage(c, "已取消收藏", nil)
+}
+
+// requireFolderOwner 检查收藏夹是否存在且属于当前用户，返回收藏夹信息
+// 如果不满足，会直接返回错误响应给客户端
+func requireFolderOwner(c *gin.Context, folderID, userID int64) (*model.CollectFolder, bool) {
+	folder, err := model.GetCollectFolderByID(pgsql.DB, folderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Folder not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to get folder")
+		return nil, false
+	}
+	if folder.UserID != userID {
+		response.NotFound(c, "Folder not found")
+		return nil, false
+	}
+	return folder, true
+}
+
+// respondCollectError 处理收藏相关操作的错误
+func respondCollectError(c *gin.Context, err error) {
+	switch err {
+	case gorm.ErrRecordNotFound:
+		response.NotFound(c, "Folder not found")
+	case model.ErrCollectFolderNameExists:
+		response.Conflict(c, "A folder with this name already exists")
+	case model.ErrCollectFolderLimit:
+		response.Conflict(c, "Folder limit reached")
+	case model.ErrPostAlreadyInFolder:
+		response.Conflict(c, "Post is already in this folder")
+	default:
+		logger.Log.Error("Failed to handle collect: " + err.Error())
+		response.InternalError(c, "Failed to handle collect")
+	}
+}
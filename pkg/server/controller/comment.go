@@ -0,0 +1,560 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultInlineReplySize = 3  // 根评论列表中默认内联的回复条数
+	maxInlineReplySize     = 10 // 根评论列表中最多内联的回复条数
+)
+
+// CommentController 处理帖子评论相关操作
+type CommentController struct{}
+
+func NewCommentController() *CommentController {
+	return &CommentController{}
+}
+
+// CommentVO 评论VO（包含评论者信息，根评论附带前几条回复）
+type CommentVO struct {
+	ID          int64                `json:"id"`
+	PostID      int64                `json:"post_id"`
+	User        UserBriefVO          `json:"user"`
+	RootID      int64                `json:"root_id"`
+	ReplyToID   int64                `json:"reply_to_id"`
+	ReplyToUser *UserBriefVO         `json:"reply_to_user,omitempty"` // 被回复的用户，回复根评论时为空
+	Content     string               `json:"content"`
+	ExtraData   model.MediaExtraJSON `json:"extra_data"` // 附件（图片、富文本结构等）
+	LikeCount   int                  `json:"like_count"`
+	ReplyCount  int                  `json:"reply_count"`
+	LikedByMe   bool                 `json:"liked_by_me"` // 当前用户是否已点赞
+	Status      int16                `json:"status"`      // 状态，非正常状态仅作者本人和管理员可见，用于展示"已折叠"/"审核中"标记
+	CreateTime  time.Time            `json:"create_time"`
+	Replies     []CommentVO          `json:"replies,omitempty"` // 前几条回复，仅根评论列表返回
+}
+
+// buildCommentList 批量查询评论内容、评论者、被回复者信息和当前用户的点赞状态，组装评论列表VO
+func buildCommentList(comments []model.Comment, viewerID int64) ([]CommentVO, error) {
+	// 1. 按评论ID批量回表查询评论内容和当前用户的点赞状态
+	commentIDs := make([]int64, 0, len(comments))
+	for _, cm := range comments {
+		commentIDs = append(commentIDs, cm.ID)
+	}
+	contents, err := model.GetCommentContents(pgsql.DB, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	liked, err := model.GetLikedCommentIDs(pgsql.DB, viewerID, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 查询被回复的评论，用于获取被回复的用户
+	replyToIDs := make([]int64, 0)
+	for _, cm := range comments {
+		if cm.ReplyToID > 0 && cm.ReplyToID != cm.RootID {
+			replyToIDs = append(replyToIDs, cm.ReplyToID)
+		}
+	}
+	replyToUser := make(map[int64]int64, len(replyToIDs))
+	if len(replyToIDs) > 0 {
+		targets, err := model.GetCommentsByIDs(pgsql.DB, replyToIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range targets {
+			replyToUser[t.ID] = t.UserID
+		}
+	}
+
+	// 3. 批量查询用户信息
+	userIDs := make([]int64, 0, len(comments)+len(replyToUser))
+	for _, cm := range comments {
+		userIDs = append(userIDs, cm.UserID)
+	}
+	for _, uid := range replyToUser {
+		userIDs = append(userIDs, uid)
+	}
+	users, err := loadUserBriefs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 组装VO
+	list := make([]CommentVO, 0, len(comments))
+	for _, cm := range comments {
+		vo := CommentVO{
+			ID:         cm.ID,
+			PostID:     cm.PostID,
+			User:       users[cm.UserID],
+			RootID:     cm.RootID,
+			ReplyToID:  cm.ReplyToID,
+			Content:    contents[cm.ID].Content,
+			ExtraData:  contents[cm.ID].ExtraData,
+			LikeCount:  cm.LikeCount,
+			ReplyCount: cm.ReplyCount,
+			LikedByMe:  liked[cm.ID],
+			Status:     cm.Status,
+			CreateTime: cm.CreateTime,
+		}
+		if uid, ok := replyToUser[cm.ReplyToID]; ok {
+			brief := users[uid]
+			vo.ReplyToUser = &brief
+		}
+		list = append(list, vo)
+	}
+	applyPendingCommentLikeCounts(list)
+	return list, nil
+}
+
+// CreateCommentRequest 发表评论的请求结构
+type CreateCommentRequest struct {
+	PostID    int64                  `json:"post_id" binding:"required,min=1"`
+	Content   string                 `json:"content" binding:"required,min=1,max=2000"`
+	ExtraData map[string]interface{} `json:"extra_data" binding:"omitempty"` // 附件（图片、富文本结构等）
+}
+
+// CreateComment 发表评论
+// POST /comment/create
+func (ctrl *CommentController) CreateComment(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查是否可以评论该帖子
+	post, ok := requireCanComment(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+
+	comment := &model.Comment{
+		PostID: post.ID,
+		UserID: int64(userID),
+		Status: model.CommentStatusNormal,
+	}
+	content := &model.CommentContent{
+		Content:   req.Content,
+		ExtraData: model.MediaExtraJSON(req.ExtraData),
+	}
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.CreateComment(tx, comment, content)
+	}, event.Comment(event.CommentCreated, comment))
+	if err != nil {
+		logger.Log.Error("Failed to create comment: " + err.Error())
+		response.InternalError(c, "Failed to create comment")
+		return
+	}
+
+	list, err := buildCommentList([]model.Comment{*comment}, int64(userID))
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Comment created successfully", list[0])
+}
+
+// ReplyCommentRequest 回复评论的请求结构
+type ReplyCommentRequest struct {
+	CommentID int64                  `json:"comment_id" binding:"required,min=1"` // 被回复的评论ID（根评论或回复）
+	Content   string                 `json:"content" binding:"required,min=1,max=2000"`
+	ExtraData map[string]interface{} `json:"extra_data" binding:"omitempty"` // 附件（图片、富文本结构等）
+}
+
+// ReplyComment 回复评论（回复统一挂在根评论下，只保留两级结构）
+// POST /comment/reply
+func (ctrl *CommentController) ReplyComment(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req ReplyCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 查询被回复的评论
+	target, err := model.GetCommentByID(pgsql.DB, req.CommentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Comment not found")
+			return
+		}
+		response.InternalError(c, "Failed to get comment")
+		return
+	}
+	if target.Status != model.CommentStatusNormal {
+		response.NotFound(c, "Comment not found")
+		return
+	}
+
+	// 2. 检查是否可以评论该帖子
+	if _, ok := requireCanComment(c, target.PostID, int64(userID)); !ok {
+		return
+	}
+
+	// 3. 创建回复
+	rootID := target.RootID
+	if rootID == 0 {
+		rootID = target.ID
+	}
+	comment := &model.Comment{
+		PostID:    target.PostID,
+		UserID:    int64(userID),
+		RootID:    rootID,
+		ReplyToID: target.ID,
+		Status:    model.CommentStatusNormal,
+	}
+	content := &model.CommentContent{
+		Content:   req.Content,
+		ExtraData: model.MediaExtraJSON(req.ExtraData),
+	}
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.CreateComment(tx, comment, content)
+	}, event.Comment(event.CommentCreated, comment))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Comment not found")
+			return
+		}
+		logger.Log.Error("Failed to reply comment: " + err.Error())
+		response.InternalError(c, "Failed to reply comment")
+		return
+	}
+
+	list, err := buildCommentList([]model.Comment{*comment}, int64(userID))
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Reply created successfully", list[0])
+}
+
+// GetCommentsRequest 获取帖子评论列表的请求结构
+type GetCommentsRequest struct {
+	PostID    int64  `form:"post_id" binding:"required,min=1"`
+	Sort      string `form:"sort" binding:"omitempty,oneof=hot newest oldest author"` // 排序方式，默认 hot
+	Cursor    string `form:"cursor"`                                                  // 上一页返回的 next_cursor，不传则从第一页开始
+	Size      int    `form:"size"`                                                    // 每页数量，默认20
+	ReplySize int    `form:"reply_size"`                                              // 每条根评论内联的回复数，默认3，最大10
+}
+
+// GetComments 游标分页获取帖子的根评论列表（每条根评论附带前几条回复）
+// GET /comment/list
+func (ctrl *CommentController) GetComments(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetCommentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.Sort == "" {
+		req.Sort = model.CommentSortHot
+	}
+	replySize := req.ReplySize
+	if replySize <= 0 {
+		replySize = defaultInlineReplySize
+	} else if replySize > maxInlineReplySize {
+		replySize = maxInlineReplySize
+	}
+
+	// 检查帖子访问权限
+	post, ok := requirePostVisible(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+	query, ok := parseCommentPageQuery(c, req.Sort, req.Cursor, req.Size, post.UserID)
+	if !ok {
+		return
+	}
+	if query.Viewer, ok = loadCommentViewer(c, post, int64(userID)); !ok {
+		return
+	}
+
+	// 1. 查询根评论
+	roots, hasMore, err := model.GetRootCommentsByPost(pgsql.DB, req.PostID, query)
+	if err != nil {
+		logger.Log.Error("Failed to get comments: " + err.Error())
+		response.InternalError(c, "Failed to get comments")
+		return
+	}
+
+	// 2. 一次性查询所有根评论的前几条回复
+	rootIDs := make([]int64, 0, len(roots))
+	for _, r := range roots {
+		rootIDs = append(rootIDs, r.ID)
+	}
+	replies, err := model.GetFirstRepliesByRoots(pgsql.DB, rootIDs, replySize, query.Viewer)
+	if err != nil {
+		logger.Log.Error("Failed to get replies: " + err.Error())
+		response.InternalError(c, "Failed to get comments")
+		return
+	}
+
+	// 3. 组装VO，将回复挂到对应的根评论下
+	all := make([]model.Comment, 0, len(roots)+len(replies))
+	all = append(all, roots...)
+	all = append(all, replies...)
+	list, err := buildCommentList(all, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build comment list: " + err.Error())
+		response.InternalError(c, "Failed to get comments")
+		return
+	}
+	rootVOs := list[:len(roots)]
+	index := make(map[int64]int, len(rootVOs))
+	for i := range rootVOs {
+		index[rootVOs[i].ID] = i
+	}
+	for _, vo := range list[len(roots):] {
+		if i, ok := index[vo.RootID]; ok {
+			rootVOs[i].Replies = append(rootVOs[i].Replies, vo)
+		}
+	}
+
+	response.CursorPagination(c, rootVOs, nextCommentCursor(req.Sort, roots, hasMore), hasMore)
+}
+
+// GetRepliesRequest 获取回复列表的请求结构
+type GetRepliesRequest struct {
+	RootID int64  `form:"root_id" binding:"required,min=1"`
+	Sort   string `form:"sort" binding:"omitempty,oneof=hot newest oldest author"` // 排序方式，默认 oldest
+	Cursor string `form:"cursor"`                                                  // 上一页返回的 next_cursor，不传则从第一页开始
+	Size   int    `form:"size"`                                                    // 每页数量，默认20
+}
+
+// GetReplies 游标分页获取根评论下的回复列表
+// GET /comment/replies
+func (ctrl *CommentController) GetReplies(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetRepliesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.Sort == "" {
+		req.Sort = model.CommentSortOldest
+	}
+
+	// 1. 查询根评论
+	root, err := model.GetCommentByID(pgsql.DB, req.RootID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Comment not found")
+			return
+		}
+		response.InternalError(c, "Failed to get comment")
+		return
+	}
+	if root.RootID != 0 {
+		response.BadRequest(c, "Comment is not a root comment")
+		return
+	}
+
+	// 2. 检查帖子访问权限
+	post, ok := requirePostVisible(c, root.PostID, int64(userID))
+	if !ok {
+		return
+	}
+	query, ok := parseCommentPageQuery(c, req.Sort, req.Cursor, req.Size, post.UserID)
+	if !ok {
+		return
+	}
+	if query.Viewer, ok = loadCommentViewer(c, post, int64(userID)); !ok {
+		return
+	}
+
+	// 3. 根评论对当前用户不可见（如已被折叠）时，其回复同样不可见
+	visible, err := model.IsCommentVisible(pgsql.DB, root.ID, query.Viewer)
+	if err != nil {
+		response.InternalError(c, "Failed to get comment")
+		return
+	}
+	if !visible {
+		response.NotFound(c, "Comment not found")
+		return
+	}
+
+	// 4. 查询回复
+	replies, hasMore, err := model.GetSubCommentsByRoot(pgsql.DB, root.ID, query)
+	if err != nil {
+		logger.Log.Error("Failed to get replies: " + err.Error())
+		response.InternalError(c, "Failed to get replies")
+		return
+	}
+
+	list, err := buildCommentList(replies, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build comment list: " + err.Error())
+		response.InternalError(c, "Failed to get replies")
+		return
+	}
+
+	response.CursorPagination(c, list, nextCommentCursor(req.Sort, replies, hasMore), hasMore)
+}
+
+// DeleteCommentRequest 删除评论的请求结构
+type DeleteCommentRequest struct {
+	CommentID int64 `json:"comment_id" binding:"required,min=1"`
+}
+
+// DeleteComment 删除自己的评论（删除根评论时一并删除其下所有回复）
+// POST /comment/delete
+func (ctrl *CommentController) DeleteComment(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req DeleteCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 1. 查询评论
+	comment, err := model.GetCommentByID(pgsql.DB, req.CommentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Comment not found")
+			return
+		}
+		response.InternalError(c, "Failed to get comment")
+		return
+	}
+
+	// 2. 只能删除自己的评论
+	if comment.UserID != int64(userID) {
+		response.Forbidden(c, "You can only delete your own comments")
+		return
+	}
+
+	// 3. 删除评论
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.DeleteComment(tx, comment)
+	}, event.Comment(event.CommentDeleted, comment))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Comment not found")
+			return
+		}
+		logger.Log.Error("Failed to delete comment: " + err.Error())
+		response.InternalError(c, "Failed to delete comment")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Comment deleted successfully", nil)
+}
+
+// commentPageToken 评论分页游标的内容，携带排序方式以防止游标在不同排序间混用
+type commentPageToken struct {
+	Sort string `json:"s"`
+	model.CommentCursor
+}
+
+// parseCommentPageQuery 解析排序方式、游标和每页数量，构建评论分页查询条件
+// 游标无效或与排序方式不匹配时，会直接返回错误响应给客户端
+func parseCommentPageQuery(c *gin.Context, sort, cursor string, size int, authorID int64) (model.CommentPageQuery, bool) {
+	_, size = normalizePage(1, size)
+	query := model.CommentPageQuery{
+		Sort:     sort,
+		AuthorID: authorID,
+		Limit:    size,
+	}
+	if cursor == "" {
+		return query, true
+	}
+
+	var token commentPageToken
+	if err := decodeCursor(cursor, &token); err != nil || token.Sort != sort {
+		response.BadRequest(c, "Invalid cursor")
+		return query, false
+	}
+	query.Cursor = &token.CommentCursor
+	return query, true
+}
+
+// nextCommentCursor 以本页最后一条评论生成下一页游标，没有下一页时返回空字符串
+func nextCommentCursor(sort string, comments []model.Comment, hasMore bool) string {
+	if !hasMore || len(comments) == 0 {
+		return ""
+	}
+	return encodeCursor(commentPageToken{
+		Sort:          sort,
+		CommentCursor: model.NewCommentCursor(&comments[len(comments)-1]),
+	})
+}
+
+// requireCanComment 检查用户是否可以评论帖子：帖子已发布且可见、未被锁定，
+// 且用户在该圈子中未被禁言或封禁；不满足时会直接返回错误响应给客户端
+func requireCanComment(c *gin.Context, postID, userID int64) (*model.Post, bool) {
+	post, ok := requirePostVisible(c, postID, userID)
+	if !ok {
+		return nil, false
+	}
+	if post.IsLock == 1 {
+		response.Forbidden(c, "This post is locked")
+		return nil, false
+	}
+
+	member, err := model.GetMember(pgsql.DB, post.CircleID, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// 未加入圈子的用户可以评论公开圈子的帖子
+			return post, true
+		}
+		response.InternalError(c, "Failed to check membership")
+		return nil, false
+	}
+
+	switch member.Status {
+	case model.MemberStatusMuted:
+		if !model.CanMemberPost(member) {
+			response.Forbidden(c, "You are muted until "+member.MuteEndTime.Format("2006-01-02 15:04:05"))
+			return nil, false
+		}
+	case model.MemberStatusBanned:
+		response.Forbidden(c, "You have been banned from this circle")
+		return nil, false
+	}
+
+	return post, true
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/storage/redis"
+	"interestBar/pkg/server/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LikeCommentRequest 点赞/取消点赞评论的请求结构
+type LikeCommentRequest struct {
+	CommentID int64 `json:"comment_id" binding:"required,min=1"`
+}
+
+// LikeComment 点赞评论（幂等，重复点赞不会重复计数）
+// POST /comment/like
+func (ctrl *CommentController) LikeComment(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req LikeCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查评论是否可见
+	comment, ok := requireCommentVisible(c, req.CommentID, int64(userID))
+	if !ok {
+		return
+	}
+
+	changed, err := model.LikeComment(pgsql.DB, int64(userID), comment.ID, comment.PostID)
+	if err != nil {
+		logger.Log.Error("Failed to like comment: " + err.Error())
+		response.InternalError(c, "Failed to like comment")
+		return
+	}
+	if changed {
+		changeCommentLikeCount(comment.ID, 1)
+	}
+
+	response.SuccessWithMessage(c, "点赞成功", nil)
+}
+
+// UnlikeComment 取消点赞评论（幂等）
+// POST /comment/unlike
+func (ctrl *CommentController) UnlikeComment(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req LikeCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	comment, err := model.GetCommentByID(pgsql.DB, req.CommentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Comment not found")
+			return
+		}
+		response.InternalError(c, "Failed to get comment")
+		return
+	}
+
+	changed, err := model.UnlikeComment(pgsql.DB, int64(userID), comment.ID)
+	if err != nil {
+		logger.Log.Error("Failed to unlike comment: " + err.Error())
+		response.InternalError(c, "Failed to unlike comment")
+		return
+	}
+	if changed {
+		changeCommentLikeCount(comment.ID, -1)
+	}
+
+	response.SuccessWithMessage(c, "已取消点赞", nil)
+}
+
+// CommentLikerVO 评论点赞者VO
+type CommentLikerVO struct {
+	User    UserBriefVO `json:"user"`
+	LikedAt time.Time   `json:"liked_at"` // 点赞时间
+}
+
+// GetCommentLikersRequest 获取评论点赞者列表的请求结构
+type GetCommentLikersRequest struct {
+	CommentID int64 `form:"comment_id" binding:"required,min=1"`
+	Page      int   `form:"page"` // 页码，默认1
+	Size      int   `form:"size"` // 每页数量，默认20
+}
+
+// GetCommentLikers 获取评论的点赞者列表（按点赞时间倒序）
+// GET /comment/likers
+func (ctrl *CommentController) GetCommentLikers(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetCommentLikersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	// 检查评论是否可见
+	comment, ok := requireCommentVisible(c, req.CommentID, int64(userID))
+	if !ok {
+		return
+	}
+
+	likes, total, err := model.GetCommentLikers(pgsql.DB, comment.ID, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get comment likers: " + err.Error())
+		response.InternalError(c, "Failed to get likers")
+		return
+	}
+
+	userIDs := make([]int64, 0, len(likes))
+	for _, l := range likes {
+		userIDs = append(userIDs, l.UserID)
+	}
+	users, err := loadUserBriefs(userIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to get user info")
+		return
+	}
+
+	list := make([]CommentLikerVO, 0, len(likes))
+	for _, l := range likes {
+		list = append(list, CommentLikerVO{
+			User:    users[l.UserID],
+			LikedAt: l.CreateTime,
+		})
+	}
+
+	response.Pagination(c, list, total, page, size)
+}
+
+// requireCommentVisible 检查评论是否存在、未在审核中且所属帖子对当前用户可见，返回评论信息
+// 如果不可见，会直接返回错误响应给客户端
+func requireCommentVisible(c *gin.Context, commentID, userID int64) (*model.Comment, bool) {
+	comment, err := model.GetCommentByID(pgsql.DB, commentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Comment not found")
+			return nil, false
+		}
+		response.InternalError(c, "Failed to get comment")
+		return nil, false
+	}
+	// 被折叠的评论仍可在折叠区查看，审核中的评论不对外展示
+	if comment.Status == model.CommentStatusReview {
+		response.NotFound(c, "Comment not found")
+		return nil, false
+	}
+
+	if _, ok := requirePostVisible(c, comment.PostID, userID); !ok {
+		return nil, false
+	}
+
+	return comment, true
+}
+
+// changeCommentLikeCount 更新评论点赞数
+// 优先累加到 Redis 由后台任务批量写回，Redis 不可用时直接更新数据库
+func changeCommentLikeCount(commentID int64, delta int64) {
+	err := redis.IncrCounter(redis.CommentLikeCounterKey, commentID, delta)
+	if err == nil {
+		return
+	}
+	logger.Log.Warn("Failed to buffer comment like count, fallback to database: " + err.Error())
+
+	if delta > 0 {
+		err = model.IncrementCommentLikeCount(pgsql.DB, commentID)
+	} else {
+		err = model.DecrementCommentLikeCount(pgsql.DB, commentID)
+	}
+	if err != nil {
+		logger.Log.Error("Failed to update comment like count: " + err.Error())
+	}
+}
+
+// applyPendingCommentLikeCounts 把尚未写回数据库的点赞数增量合并到评论VO中
+func applyPendingCommentLikeCounts(list []CommentVO) {
+	commentIDs := make([]int64, 0, len(list))
+	for _, vo := range list {
+		commentIDs = append(commentIDs, vo.ID)
+	}
+
+	deltas, err := redis.GetCounters(redis.CommentLikeCounterKey, commentIDs)
+	if err != nil {
+		// 仅影响展示的实时性，不影响主流程
+		logger.Log.Warn("Failed to get pending comment like counts: " + err.Error())
+		return
+	}
+
+	for i := range list {
+		list[i].LikeCount += int(deltas[list[i].ID])
+		if list[i].LikeCount < 0 {
+			list[i].LikeCount = 0
+		}
+	}
+}
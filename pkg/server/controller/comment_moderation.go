@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"fmt"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ModerateCommentRequest 管理评论（折叠、送审、恢复、删除）的请求结构
+type ModerateCommentRequest struct {
+	CommentID int64  `json:"comment_id" binding:"required,min=1"`
+	Reason    string `json:"reason" binding:"max=200"` // 处理原因，会通知给评论作者
+}
+
+// FoldComment 折叠评论（折叠后只在"已折叠评论"中展示，不计入评论数）
+// POST /comment/fold
+func (ctrl *CommentController) FoldComment(c *gin.Context) {
+	ctrl.setCommentStatus(c, model.CommentStatusHidden, "已被折叠")
+}
+
+// ReviewComment 将评论送审（审核期间仅作者本人和管理员可见）
+// POST /comment/review
+func (ctrl *CommentController) ReviewComment(c *gin.Context) {
+	ctrl.setCommentStatus(c, model.CommentStatusReview, "已被送审")
+}
+
+// RestoreComment 恢复被折叠或送审的评论
+// POST /comment/restore
+func (ctrl *CommentController) RestoreComment(c *gin.Context) {
+	ctrl.setCommentStatus(c, model.CommentStatusNormal, "")
+}
+
+// setCommentStatus 修改评论状态的公共逻辑，action 为通知作者时的操作描述，为空时不通知
+func (ctrl *CommentController) setCommentStatus(c *gin.Context, status int16, action string) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req ModerateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查管理权限
+	comment, post, ok := requireCommentModerator(c, req.CommentID, int64(userID))
+	if !ok {
+		return
+	}
+
+	var notification *model.Notification
+	if action != "" && comment.UserID != int64(userID) {
+		notification = newCommentNotification(comment, post, action, req.Reason)
+	}
+
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.SetCommentStatus(tx, comment.ID, status, notification)
+	}, event.Comment(event.CommentStatusChanged, comment))
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			response.NotFound(c, "Comment not found")
+		case model.ErrCommentStatusUnchanged:
+			response.Conflict(c, "Comment is already in this status")
+		default:
+			logger.Log.Error("Failed to moderate comment: " + err.Error())
+			response.InternalError(c, "Failed to moderate comment")
+		}
+		return
+	}
+
+	response.SuccessWithMessage(c, "操作成功", nil)
+}
+
+// RemoveComment 管理员删除圈子内的任意评论（删除根评论时一并删除其下所有回复）
+// POST /comment/remove
+func (ctrl *CommentController) RemoveComment(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req ModerateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查管理权限
+	comment, post, ok := requireCommentModerator(c, req.CommentID, int64(userID))
+	if !ok {
+		return
+	}
+
+	var notification *model.Notification
+	if comment.UserID != int64(userID) {
+		notification = newCommentNotification(comment, post, "已被删除", req.Reason)
+	}
+
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.RemoveComment(tx, comment, notification)
+	}, event.Comment(event.CommentDeleted, comment))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Comment not found")
+			return
+		}
+		logger.Log.Error("Failed to remove comment: " + err.Error())
+		response.InternalError(c, "Failed to remove comment")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Comment deleted successfully", nil)
+}
+
+// GetFoldedCommentsRequest 获取已折叠评论的请求结构
+type GetFoldedCommentsRequest struct {
+	PostID int64  `form:"post_id" binding:"required,min=1"`
+	RootID int64  `form:"root_id" binding:"omitempty,min=1"` // 传入时查询该根评论下被折叠的回复，否则查询被折叠的根评论
+	Cursor string `form:"cursor"`                            // 上一页返回的 next_cursor，不传则从第一页开始
+	Size   int    `form:"size"`                              // 每页数量，默认20
+}
+
+// GetFoldedComments 游标分页获取已折叠的评论（对应列表底部的"已折叠评论"入口）
+// GET /comment/folded
+func (ctrl *CommentController) GetFoldedComments(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetFoldedCommentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	// 检查帖子访问权限
+	post, ok := requirePostVisible(c, req.PostID, int64(userID))
+	if !ok {
+		return
+	}
+	query, ok := parseCommentPageQuery(c, model.CommentSortOldest, req.Cursor, req.Size, post.UserID)
+	if !ok {
+		return
+	}
+	query.Folded = true
+
+	var comments []model.Comment
+	var hasMore bool
+	var err error
+	if req.RootID > 0 {
+		root, rootErr := model.GetCommentByID(pgsql.DB, req.RootID)
+		if rootErr != nil || root.PostID != post.ID || root.RootID != 0 {
+			if rootErr != nil && rootErr != gorm.ErrRecordNotFound {
+				response.InternalError(c, "Failed to get comment")
+				return
+			}
+			response.NotFound(c, "Comment not found")
+			return
+		}
+		comments, hasMore, err = model.GetSubCommentsByRoot(pgsql.DB, root.ID, query)
+	} else {
+		comments, hasMore, err = model.GetRootCommentsByPost(pgsql.DB, post.ID, query)
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get folded comments: " + err.Error())
+		response.InternalError(c, "Failed to get comments")
+		return
+	}
+
+	list, err := buildCommentList(comments, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build comment list: " + err.Error())
+		response.InternalError(c, "Failed to get comments")
+		return
+	}
+
+	response.CursorPagination(c, list, nextCommentCursor(model.CommentSortOldest, comments, hasMore), hasMore)
+}
+
+// requireCommentModerator 检查评论是否存在且当前用户是评论所属圈子的管理员，返回评论和帖子信息
+// 如果没有权限，会直接返回错误响应给客户端
+func requireCommentModerator(c *gin.Context, commentID, userID int64) (*model.Comment, *model.Post, bool) {
+	comment, err := model.GetCommentByID(pgsql.DB, commentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			response.NotFound(c, "Comment not found")
+			return nil, nil, false
+		}
+		response.InternalError(c, "Failed to get comment")
+		return nil, nil, false
+	}
+
+	post, ok := requirePostModerator(c, comment.PostID, userID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return comment, post, true
+}
+
+// loadCommentViewer 构建评论列表的查看者信息（圈子管理员可以看到审核中的评论）
+func loadCommentViewer(c *gin.Context, post *model.Post, userID int64) (model.CommentViewer, bool) {
+	viewer := model.CommentViewer{UserID: userID}
+
+	member, err := model.GetMember(pgsql.DB, post.CircleID, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return viewer, true
+		}
+		response.InternalError(c, "Failed to check membership")
+		return viewer, false
+	}
+
+	viewer.Moderator = member.Status == model.MemberStatusNormal && member.Role >= model.MemberRoleAdmin
+	return viewer, true
+}
+
+// newCommentNotification 构建评论被管理时发给作者的通知
+func newCommentNotification(comment *model.Comment, post *model.Post, action, reason string) *model.Notification {
+	content := fmt.Sprintf("你在帖子「%s」下的评论%s", post.Title, action)
+	if reason != "" {
+		content += "，原因：" + reason
+	}
+	return &model.Notification{
+		UserID:    comment.UserID,
+		Type:      model.NotificationTypeComment,
+		Title:     "评论" + action,
+		Content:   content,
+		CircleID:  post.CircleID,
+		RelatedID: comment.ID,
+	}
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/storage/db/pgsql"
+)
+
+// 分页默认值
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// normalizePage 校正分页参数，page 从 1 开始，size 默认 20，最大 100
+func normalizePage(page, size int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 || size > maxPageSize {
+		size = defaultPageSize
+	}
+	return page, size
+}
+
+// UserBriefVO 用户简要信息（用于列表中展示作者/申请人等）
+type UserBriefVO struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// loadUserBriefs 批量查询用户简要信息，返回以用户ID为键的 map
+func loadUserBriefs(userIDs []int64) (map[int64]UserBriefVO, error) {
+	users, err := model.GetUsersByIDs(pgsql.DB, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	briefs := make(map[int64]UserBriefVO, len(users))
+	for _, u := range users {
+		briefs[u.ID] = UserBriefVO{
+			ID:        u.ID,
+			Username:  u.Username,
+			AvatarURL: u.AvatarURL,
+		}
+	}
+	return briefs, nil
+}
+
+// encodeCursor 将游标位置编码为不透明字符串（JSON + URL 安全的 Base64），客户端原样回传即可
+func encodeCursor(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor 解析客户端回传的游标字符串
+func decodeCursor(cursor string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
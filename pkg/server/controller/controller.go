@@ -1,6 +1,11 @@
 package controller
 
-import "github.com/gin-gonic/gin"
+import (
+	"interestBar/pkg/server/storage/rabbitmq"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
 
 // Controller interface used as a marker or base.
 type Controller interface{}
@@ -15,3 +20,13 @@ func NewSystemController() *SystemController {
 func (ctrl *SystemController) HealthCheck(c *gin.Context) {
 	c.JSON(200, gin.H{"status": "ok"})
 }
+
+// ReadinessCheck 就绪检查，RabbitMQ 断线时返回 503，恢复连接后自动就绪
+func (ctrl *SystemController) ReadinessCheck(c *gin.Context) {
+	mq := rabbitmq.HealthStatus()
+	if !mq.Connected {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "rabbitmq": mq})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "rabbitmq": mq})
+}
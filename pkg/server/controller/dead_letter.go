@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"errors"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/storage/rabbitmq"
+	"interestBar/pkg/server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeadLetterController 处理消息队列死信的查看、重放和清除（平台管理员）
+type DeadLetterController struct{}
+
+func NewDeadLetterController() *DeadLetterController {
+	return &DeadLetterController{}
+}
+
+// GetDeadLettersRequest 查看死信的请求结构
+type GetDeadLettersRequest struct {
+	Subscriber string `form:"subscriber" binding:"required"`           // 订阅者名，如 search
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"` // 查看数量，默认20
+}
+
+// GetDeadLettersResponse 查看死信的响应结构
+type GetDeadLettersResponse struct {
+	Subscriber string                `json:"subscriber"`
+	Total      int                   `json:"total"` // 死信总数
+	List       []rabbitmq.DeadLetter `json:"list"`
+}
+
+// GetDeadLetters 查看订阅者死信队列中的消息（平台管理员）
+// GET /admin/mq/dead/list
+func (ctrl *DeadLetterController) GetDeadLetters(c *gin.Context) {
+	if !requirePlatformAdmin(c) {
+		return
+	}
+
+	// 解析请求参数
+	var req GetDeadLettersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	letters, total, err := rabbitmq.PeekDeadLetters(req.Subscriber, req.Limit)
+	if err != nil {
+		respondDeadLetterError(c, err, "Failed to get dead letters")
+		return
+	}
+	if letters == nil {
+		letters = []rabbitmq.DeadLetter{}
+	}
+
+	response.Success(c, GetDeadLettersResponse{
+		Subscriber: req.Subscriber,
+		Total:      total,
+		List:       letters,
+	})
+}
+
+// ReplayDeadLettersRequest 重放死信的请求结构
+type ReplayDeadLettersRequest struct {
+	Subscriber string   `json:"subscriber" binding:"required"`
+	EventIDs   []string `json:"event_ids"`                                // 指定重放的事件，不传则按顺序重放
+	Limit      int      `json:"limit" binding:"omitempty,min=1,max=1000"` // 最多重放数量，默认100
+}
+
+// ReplayDeadLetters 把死信重新投递给订阅者处理（平台管理员）
+// POST /admin/mq/dead/replay
+func (ctrl *DeadLetterController) ReplayDeadLetters(c *gin.Context) {
+	if !requirePlatformAdmin(c) {
+		return
+	}
+
+	// 解析请求参数
+	var req ReplayDeadLettersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+
+	replayed, err := rabbitmq.ReplayDeadLetters(req.Subscriber, req.EventIDs, req.Limit)
+	if err != nil {
+		respondDeadLetterError(c, err, "Failed to replay dead letters")
+		return
+	}
+
+	response.SuccessWithMessage(c, "重放成功", gin.H{"count": replayed})
+}
+
+// PurgeDeadLettersRequest 清除死信的请求结构
+type PurgeDeadLettersRequest struct {
+	Subscriber string   `json:"subscriber" binding:"required"`
+	EventIDs   []string `json:"event_ids"` // 指定删除的事件，不传则清空死信队列
+}
+
+// PurgeDeadLetters 删除订阅者的死信（平台管理员）
+// POST /admin/mq/dead/purge
+func (ctrl *DeadLetterController) PurgeDeadLetters(c *gin.Context) {
+	if !requirePlatformAdmin(c) {
+		return
+	}
+
+	// 解析请求参数
+	var req PurgeDeadLettersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	purged, err := rabbitmq.PurgeDeadLetters(req.Subscriber, req.EventIDs)
+	if err != nil {
+		respondDeadLetterError(c, err, "Failed to purge dead letters")
+		return
+	}
+
+	response.SuccessWithMessage(c, "清除成功", gin.H{"count": purged})
+}
+
+// requirePlatformAdmin 校验当前用户是否为平台管理员，校验失败时已写入响应
+func requirePlatformAdmin(c *gin.Context) bool {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return false
+	}
+
+	isPlatformAdmin, err := model.IsPlatformAdmin(pgsql.DB, int64(userID))
+	if err != nil {
+		response.InternalError(c, "Failed to check permission")
+		return false
+	}
+	if !isPlatformAdmin {
+		response.Forbidden(c, "Only platform admins can manage message queues")
+		return false
+	}
+	return true
+}
+
+// respondDeadLetterError 根据死信操作的错误类型返回响应
+func respondDeadLetterError(c *gin.Context, err error, message string) {
+	if errors.Is(err, rabbitmq.ErrUnknownSubscriber) {
+		response.NotFound(c, "Subscriber not found")
+		return
+	}
+	logger.Log.Error(message + ": " + err.Error())
+	response.InternalError(c, message)
+}
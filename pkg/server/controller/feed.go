@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/storage/redis"
+	"interestBar/pkg/server/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 首页信息流参数
+const (
+	feedWindow    = 30 * 24 * time.Hour // 只聚合最近30天发布的帖子
+	feedTopBoost  = 12 * time.Hour      // 置顶圈子的帖子排序相当于提前12小时发布
+	feedCacheSize = 300                 // 每个用户缓存的条目数，翻过缓存部分后直接查库
+	feedCacheTTL  = 5 * time.Minute     // 缓存有效期，过期后下次请求重建
+)
+
+// FeedController 处理首页信息流
+type FeedController struct{}
+
+func NewFeedController() *FeedController {
+	return &FeedController{}
+}
+
+// GetHomeFeedRequest 获取首页信息流的请求结构
+type GetHomeFeedRequest struct {
+	Cursor string `form:"cursor"` // 上一页返回的 next_cursor，不传表示第一页
+	Size   int    `form:"size"`   // 每页数量，默认20
+}
+
+// GetHomeFeed 获取首页信息流（聚合已加入圈子的最新帖子，置顶圈子的帖子排序提前）
+// GET /feed/home
+func (ctrl *FeedController) GetHomeFeed(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetHomeFeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	_, size := normalizePage(1, req.Size)
+
+	var cursor *model.FeedItem
+	if req.Cursor != "" {
+		cursor = &model.FeedItem{}
+		if err := decodeCursor(req.Cursor, cursor); err != nil {
+			response.BadRequest(c, "Invalid cursor")
+			return
+		}
+	}
+
+	// 1. 每次请求都重新读取成员关系，已退出或被移出的圈子的帖子立即不再展示
+	memberships, err := model.GetCirclesByUserID(pgsql.DB, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to get joined circles: " + err.Error())
+		response.InternalError(c, "Failed to get feed")
+		return
+	}
+	if len(memberships) == 0 {
+		response.CursorPagination(c, []PostVO{}, "", false)
+		return
+	}
+
+	query := model.FeedQuery{
+		TopBoost: feedTopBoost,
+		Since:    time.Now().Add(-feedWindow),
+	}
+	joined := make(map[int64]bool, len(memberships))
+	for _, m := range memberships {
+		query.CircleIDs = append(query.CircleIDs, m.CircleID)
+		if m.IsTop == 1 {
+			query.TopCircleIDs = append(query.TopCircleIDs, m.CircleID)
+		}
+		joined[m.CircleID] = true
+	}
+
+	// 2. 多取一条用于判断是否还有下一页
+	items, err := loadFeedItems(int64(userID), query, cursor, size+1)
+	if err != nil {
+		logger.Log.Error("Failed to load feed: " + err.Error())
+		response.InternalError(c, "Failed to get feed")
+		return
+	}
+	hasMore := len(items) > size
+	if hasMore {
+		items = items[:size]
+	}
+	nextCursor := ""
+	if hasMore {
+		nextCursor = encodeCursor(items[len(items)-1])
+	}
+
+	// 3. 按信息流顺序组装帖子，缓存期间被删除或下架的帖子跳过
+	postIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		postIDs = append(postIDs, item.PostID)
+	}
+	found, err := model.GetPostsByIDs(pgsql.DB, postIDs)
+	if err != nil {
+		logger.Log.Error("Failed to get feed posts: " + err.Error())
+		response.InternalError(c, "Failed to get feed")
+		return
+	}
+	byID := make(map[int64]model.Post, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+	posts := make([]model.Post, 0, len(items))
+	for _, item := range items {
+		p, ok := byID[item.PostID]
+		if !ok || p.Status != model.PostStatusPublished || !joined[p.CircleID] {
+			continue
+		}
+		posts = append(posts, p)
+	}
+
+	list, err := buildPostList(posts, int64(userID))
+	if err != nil {
+		logger.Log.Error("Failed to build post list: " + err.Error())
+		response.InternalError(c, "Failed to get feed")
+		return
+	}
+
+	response.CursorPagination(c, list, nextCursor, hasMore)
+}
+
+// loadFeedItems 读取游标之后的 limit 条信息流条目
+// 优先读取用户的信息流缓存，缓存不存在时从数据库聚合并重建缓存；翻过缓存部分或 Redis 不可用时直接查库
+func loadFeedItems(userID int64, query model.FeedQuery, cursor *model.FeedItem, limit int) ([]model.FeedItem, error) {
+	var cursorScore, cursorID int64
+	if cursor != nil {
+		cursorScore, cursorID = cursor.Score, cursor.PostID
+	}
+	entries, exists, err := redis.GetFeed(userID, cursor != nil, cursorScore, cursorID, limit)
+	if err != nil {
+		logger.Log.Warn("Failed to read feed cache, fallback to database: " + err.Error())
+		query.Cursor = cursor
+		query.Limit = limit
+		return model.GetFeedItems(pgsql.DB, query)
+	}
+
+	if !exists {
+		return rebuildFeedCache(userID, query, cursor, limit)
+	}
+
+	items := make([]model.FeedItem, 0, limit)
+	for _, e := range entries {
+		if e.PostID == redis.FeedEndMarker {
+			// 缓存已包含全部条目
+			return items, nil
+		}
+		items = append(items, model.FeedItem{PostID: e.PostID, Score: e.Score})
+	}
+	if len(items) >= limit {
+		return items, nil
+	}
+
+	// 缓存只保存了前 feedCacheSize 条，剩余部分继续查库
+	return appendFeedFromDB(query, cursor, items, limit)
+}
+
+// rebuildFeedCache 从数据库聚合前 feedCacheSize 条信息流写入缓存，并返回游标之后的 limit 条
+func rebuildFeedCache(userID int64, query model.FeedQuery, cursor *model.FeedItem, limit int) ([]model.FeedItem, error) {
+	query.Limit = feedCacheSize
+	all, err := model.GetFeedItems(pgsql.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]redis.FeedEntry, 0, len(all))
+	for _, item := range all {
+		entries = append(entries, redis.FeedEntry{PostID: item.PostID, Score: item.Score})
+	}
+	complete := len(all) < feedCacheSize
+	if err := redis.SetFeed(userID, entries, complete, feedCacheTTL); err != nil {
+		// 仅影响后续请求的性能，不影响本次结果
+		logger.Log.Warn("Failed to write feed cache: " + err.Error())
+	}
+
+	// 在聚合结果中定位游标
+	start := 0
+	if cursor != nil {
+		start = len(all)
+		for i, item := range all {
+			if item.Score < cursor.Score || (item.Score == cursor.Score && item.PostID < cursor.PostID) {
+				start = i
+				break
+			}
+		}
+	}
+	items := all[start:]
+	if len(items) >= limit {
+		return items[:limit], nil
+	}
+	if complete {
+		return items, nil
+	}
+
+	// 游标已超出缓存范围，剩余部分继续查库
+	return appendFeedFromDB(query, cursor, items, limit)
+}
+
+// appendFeedFromDB 从已取到的最后一条（没有则从游标）开始查库，补足 limit 条
+func appendFeedFromDB(query model.FeedQuery, cursor *model.FeedItem, items []model.FeedItem, limit int) ([]model.FeedItem, error) {
+	query.Cursor = cursor
+	if len(items) > 0 {
+		query.Cursor = &items[len(items)-1]
+	}
+	query.Limit = limit - len(items)
+	more, err := model.GetFeedItems(pgsql.DB, query)
+	if err != nil {
+		return nil, err
+	}
+	return append(items, more...), nil
+}
+
+// invalidateHomeFeed 删除用户的信息流缓存（加入圈子或修改置顶设置后调用），下次请求时重建
+func invalidateHomeFeed(userID int64) {
+	if err := redis.DelFeed(userID); err != nil {
+		logger.Log.Warn("Failed to invalidate feed cache: " + err.Error())
+	}
+}
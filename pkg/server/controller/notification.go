@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/response"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController 处理站内通知相关操作
+type NotificationController struct{}
+
+func NewNotificationController() *NotificationController {
+	return &NotificationController{}
+}
+
+// GetNotificationsRequest 获取通知列表的请求结构
+type GetNotificationsRequest struct {
+	UnreadOnly bool `form:"unread_only"` // 是否只看未读
+	Page       int  `form:"page"`        // 页码，默认1
+	Size       int  `form:"size"`        // 每页数量，默认20
+}
+
+// GetNotifications 获取我的通知列表
+// GET /notification/list
+func (ctrl *NotificationController) GetNotifications(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req GetNotificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	page, size := normalizePage(req.Page, req.Size)
+
+	notifications, total, err := model.GetNotificationsByUser(pgsql.DB, int64(userID), req.UnreadOnly, page, size)
+	if err != nil {
+		logger.Log.Error("Failed to get notifications: " + err.Error())
+		response.InternalError(c, "Failed to get notifications")
+		return
+	}
+
+	response.Pagination(c, notifications, total, page, size)
+}
+
+// GetUnreadCount 获取未读通知数
+// GET /notification/unread
+func (ctrl *NotificationController) GetUnreadCount(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	count, err := model.CountUnreadNotifications(pgsql.DB, int64(userID))
+	if err != nil {
+		response.InternalError(c, "Failed to count notifications")
+		return
+	}
+
+	response.Success(c, gin.H{"unread": count})
+}
+
+// MarkReadRequest 标记通知已读的请求结构
+type MarkReadRequest struct {
+	IDs []int64 `json:"ids" binding:"omitempty,max=100"` // 通知ID列表，为空表示全部已读
+}
+
+// MarkRead 标记通知为已读
+// POST /notification/read
+func (ctrl *NotificationController) MarkRead(c *gin.Context) {
+	// 获取当前登录用户ID
+	userID, ok := utils.GetUserIDFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// 解析请求参数
+	var req MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	if err := model.MarkNotificationsRead(pgsql.DB, int64(userID), req.IDs); err != nil {
+		logger.Log.Error("Failed to mark notifications read: " + err.Error())
+		response.InternalError(c, "Failed to mark notifications read")
+		return
+	}
+
+	response.SuccessWithMessage(c, "已标记为已读", nil)
+}
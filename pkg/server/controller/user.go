@@ -4,6 +4,7 @@ import (
 	"interestBar/pkg/conf"
 	"interestBar/pkg/logger"
 	"interestBar/pkg/server/auth"
+	"interestBar/pkg/server/event"
 	"interestBar/pkg/server/model"
 	"interestBar/pkg/server/response"
 	"interestBar/pkg/server/storage/db/pgsql"
@@ -365,8 +366,11 @@ func (ctrl *UserController) UpdateProfile(c *gin.Context) {
 		updateData["birthdate"] = *req.Birthdate
 	}
 
-	// 更新数据库
-	if err := pgsql.DB.Model(&user).Updates(updateData).Error; err != nil {
+	// 更新数据库，同一事务写入用户资料变更事件
+	err := event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return tx.Model(&user).Updates(updateData).Error
+	}, event.User(event.UserUpdated, int64(userID)))
+	if err != nil {
 		response.InternalError(c, "Failed to update user info")
 		return
 	}
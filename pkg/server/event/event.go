@@ -0,0 +1,167 @@
+package event
+
+import (
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/storage/rabbitmq"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 领域事件类型，同时用于 RabbitMQ topic 路由，订阅者可以用 "post.*" 这样的模式订阅一类事件
+const (
+	CircleCreated = "circle.created" // 圈子创建
+	CircleUpdated = "circle.updated" // 圈子资料、状态或统计字段变更
+	CircleDeleted = "circle.deleted" // 圈子解散
+
+	PostCreated       = "post.created"        // 帖子发布（含草稿发布、定时发布）
+	PostUpdated       = "post.updated"        // 帖子内容编辑
+	PostStatusChanged = "post.status_changed" // 帖子审核、屏蔽、加精等状态变更
+	PostDeleted       = "post.deleted"        // 帖子删除
+
+	CommentCreated       = "comment.created"        // 发表评论或回复
+	CommentStatusChanged = "comment.status_changed" // 评论折叠、审核、恢复
+	CommentDeleted       = "comment.deleted"        // 评论删除
+
+	MemberJoined        = "member.joined"         // 成员加入（直接加入、审核通过、邀请码加入）
+	MemberLeft          = "member.left"           // 成员退出或被踢出
+	MemberStatusChanged = "member.status_changed" // 成员被禁言、拉黑或解除
+
+	UserUpdated = "user.updated" // 用户资料变更
+)
+
+// 各事件载荷的当前版本
+const (
+	CircleVersion  = 1
+	PostVersion    = 1
+	CommentVersion = 1
+	MemberVersion  = 1
+	UserVersion    = 1
+)
+
+// CirclePayload 圈子事件载荷
+type CirclePayload struct {
+	CircleID int64 `json:"circle_id"`
+}
+
+// PostPayload 帖子事件载荷
+type PostPayload struct {
+	PostID   int64 `json:"post_id"`
+	CircleID int64 `json:"circle_id"`
+	UserID   int64 `json:"user_id"` // 帖子作者
+}
+
+// CommentPayload 评论事件载荷
+type CommentPayload struct {
+	CommentID int64 `json:"comment_id"`
+	PostID    int64 `json:"post_id"`
+	UserID    int64 `json:"user_id"` // 评论作者
+}
+
+// MemberPayload 成员事件载荷
+type MemberPayload struct {
+	CircleID int64 `json:"circle_id"`
+	UserID   int64 `json:"user_id"`
+	Status   int16 `json:"status"` // 变更后的成员状态，退出时为 0
+}
+
+// UserPayload 用户事件载荷
+type UserPayload struct {
+	UserID int64 `json:"user_id"`
+}
+
+// Pending 待写入发件箱的事件，在业务写操作之后求值，以便读取写操作生成的ID
+type Pending func() (*model.OutboxEvent, error)
+
+// New 构建发件箱事件，聚合类型取事件类型的前缀（如 post.created 的聚合类型为 post）
+// 载荷只携带ID，订阅者按需读取最新数据，重复或乱序的事件不会导致数据回退
+func New(eventType string, version int, aggregateID int64, payload interface{}) (*model.OutboxEvent, error) {
+	e, err := rabbitmq.NewEvent(eventType, version, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregateType := eventType
+	if i := strings.Index(eventType, "."); i > 0 {
+		aggregateType = eventType[:i]
+	}
+
+	return &model.OutboxEvent{
+		EventID:       e.ID,
+		EventType:     e.Type,
+		Version:       e.Version,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       string(e.Payload),
+		Status:        model.OutboxStatusPending,
+		OccurredAt:    e.OccurredAt,
+		NextRetryTime: e.OccurredAt,
+	}, nil
+}
+
+// Circle 圈子事件
+func Circle(eventType string, circle *model.Circle) Pending {
+	return func() (*model.OutboxEvent, error) {
+		return New(eventType, CircleVersion, circle.ID, CirclePayload{CircleID: circle.ID})
+	}
+}
+
+// Post 帖子事件
+func Post(eventType string, post *model.Post) Pending {
+	return func() (*model.OutboxEvent, error) {
+		return New(eventType, PostVersion, post.ID, PostPayload{
+			PostID:   post.ID,
+			CircleID: post.CircleID,
+			UserID:   post.UserID,
+		})
+	}
+}
+
+// Comment 评论事件
+func Comment(eventType string, comment *model.Comment) Pending {
+	return func() (*model.OutboxEvent, error) {
+		return New(eventType, CommentVersion, comment.ID, CommentPayload{
+			CommentID: comment.ID,
+			PostID:    comment.PostID,
+			UserID:    comment.UserID,
+		})
+	}
+}
+
+// Member 成员事件，同一圈子的成员事件按写入顺序投递
+func Member(eventType string, circleID, userID int64, status int16) Pending {
+	return func() (*model.OutboxEvent, error) {
+		return New(eventType, MemberVersion, circleID, MemberPayload{
+			CircleID: circleID,
+			UserID:   userID,
+			Status:   status,
+		})
+	}
+}
+
+// User 用户事件
+func User(eventType string, userID int64) Pending {
+	return func() (*model.OutboxEvent, error) {
+		return New(eventType, UserVersion, userID, UserPayload{UserID: userID})
+	}
+}
+
+// Save 在同一事务中执行业务写操作并把事件写入发件箱
+// 写操作失败时事件不会写入；事务提交后事件由发件箱中继任务投递到 RabbitMQ
+func Save(db *gorm.DB, write func(tx *gorm.DB) error, events ...Pending) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+
+		outbox := make([]*model.OutboxEvent, 0, len(events))
+		for _, pending := range events {
+			e, err := pending()
+			if err != nil {
+				return err
+			}
+			outbox = append(outbox, e)
+		}
+		return model.CreateOutboxEvents(tx, outbox)
+	})
+}
@@ -0,0 +1,122 @@
+package job
+
+import (
+	"fmt"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/storage/redis"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// counterFlushInterval 计数器写回间隔
+	counterFlushInterval = 5 * time.Second
+	// counterLockTTL 写回锁的过期时间，持有锁的实例崩溃后由其他实例接管
+	counterLockTTL = 30 * time.Second
+	// counterBatchRetention 写回批次记录的保留时长
+	counterBatchRetention = 24 * time.Hour
+	// counterCleanupInterval 清理写回批次记录的间隔
+	counterCleanupInterval = time.Hour
+)
+
+// counterFlusher 计数器写回配置：Redis 增量键与对应的数据库写回函数
+type counterFlusher struct {
+	key   string
+	apply func(db *gorm.DB, deltas map[int64]int64) error
+}
+
+// counterFlushers 需要定期写回的计数器
+var counterFlushers = []counterFlusher{
+	{key: redis.PostLikeCounterKey, apply: model.ApplyLikeCountDeltas},
+	{key: redis.CommentLikeCounterKey, apply: model.ApplyCommentLikeCountDeltas},
+	{key: redis.PollOptionCounterKey, apply: model.ApplyPollOptionCountDeltas},
+	{key: redis.PollVoterCounterKey, apply: model.ApplyPollVoterCountDeltas},
+}
+
+// StartCounterFlusher 启动计数器写回任务
+// 周期性地把 Redis 中累积的计数增量批量写回 PostgreSQL
+func StartCounterFlusher() {
+	logger.Log.Info("Counter flusher started")
+
+	ticker := time.NewTicker(counterFlushInterval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for range ticker.C {
+		for _, f := range counterFlushers {
+			flushCounter(f)
+		}
+
+		if time.Since(lastCleanup) >= counterCleanupInterval {
+			cleanupCounterFlushes()
+			lastCleanup = time.Now()
+		}
+	}
+}
+
+// flushCounter 写回单个计数器的增量，失败时保留快照等待下次重试
+// 写回锁保证同一时间只有一个实例在写回；批次号与增量在同一事务中登记，
+// 锁过期被接管或写回后未来得及删除快照时，同一批次不会被重复计入
+func flushCounter(f counterFlusher) {
+	lock, ok, err := redis.LockCounter(f.key, counterLockTTL)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Failed to lock counters: key=%s, err=%s", f.key, err.Error()))
+		return
+	}
+	if !ok {
+		return
+	}
+	defer func() {
+		if err := redis.UnlockCounter(f.key, lock); err != nil {
+			logger.Log.Error(fmt.Sprintf("Failed to unlock counters: key=%s, err=%s", f.key, err.Error()))
+		}
+	}()
+
+	batch, deltas, err := redis.TakeCounters(f.key)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Failed to take counters: key=%s, err=%s", f.key, err.Error()))
+		return
+	}
+
+	if len(deltas) > 0 {
+		err = pgsql.DB.Transaction(func(tx *gorm.DB) error {
+			claimed, err := model.ClaimCounterFlush(tx, batch, f.key)
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				logger.Log.Warn(fmt.Sprintf("Counters already flushed, skipping: key=%s, batch=%s", f.key, batch))
+				return nil
+			}
+			return f.apply(tx, deltas)
+		})
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("Failed to flush counters: key=%s, err=%s", f.key, err.Error()))
+			return
+		}
+	}
+
+	if err := redis.AckCounters(f.key); err != nil {
+		logger.Log.Error(fmt.Sprintf("Failed to ack counters: key=%s, err=%s", f.key, err.Error()))
+		return
+	}
+
+	if len(deltas) > 0 {
+		logger.Log.Debug(fmt.Sprintf("Counters flushed: key=%s, rows=%d", f.key, len(deltas)))
+	}
+}
+
+// cleanupCounterFlushes 清理超过保留时长的写回批次记录
+func cleanupCounterFlushes() {
+	deleted, err := model.DeleteCounterFlushes(pgsql.DB, time.Now().Add(-counterBatchRetention))
+	if err != nil {
+		logger.Log.Error("Failed to clean up counter flushes: " + err.Error())
+		return
+	}
+	if deleted > 0 {
+		logger.Log.Info(fmt.Sprintf("Counter flushes cleaned up: %d", deleted))
+	}
+}
@@ -0,0 +1,129 @@
+package job
+
+import (
+	"fmt"
+	"interestBar/pkg/conf"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/storage/elasticsearch"
+	"time"
+)
+
+// 热度计算的默认配置，配置文件中未设置(为0)时使用
+const (
+	defaultHotScoreInterval = 600 // 计算间隔(秒)
+	defaultHotScoreWindow   = 7   // 统计窗口(天)
+	defaultPostHalfLife     = 24  // 帖子热度半衰期(小时)
+	defaultCircleHalfLife   = 72  // 圈子活跃度半衰期(小时)
+)
+
+// 默认权重，配置文件中未设置任何权重时使用
+var (
+	defaultPostWeights   = conf.HotScorePost{View: 1, Like: 5, Comment: 10, Collect: 20}
+	defaultCircleWeights = conf.HotScoreCircle{NewPost: 10, ActiveMember: 5, MemberGrowth: 3}
+)
+
+// 写回数据库后推送 ES 失败的热度，下一轮重试
+var (
+	pendingPostHot   = make(map[int64]int)
+	pendingCircleHot = make(map[int64]int)
+)
+
+// StartHotScoreJob 启动热度计算任务
+// 周期性地重新计算帖子和圈子的热度，写回 PostgreSQL 后把变化的部分批量推送到 Elasticsearch
+// 每轮都重新读取配置，修改权重、半衰期或间隔后无需重启
+func StartHotScoreJob() {
+	logger.Log.Info("Hot score job started")
+
+	for {
+		refreshHotScores()
+		time.Sleep(hotScoreInterval())
+	}
+}
+
+// hotScoreInterval 获取热度计算间隔
+func hotScoreInterval() time.Duration {
+	interval := conf.Config.HotScore.Interval
+	if interval <= 0 {
+		interval = defaultHotScoreInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// hotScoreParams 根据配置构建热度计算参数
+func hotScoreParams() model.HotScoreParams {
+	cfg := conf.Config.HotScore
+
+	window := cfg.WindowDays
+	if window <= 0 {
+		window = defaultHotScoreWindow
+	}
+	postHalfLife := cfg.PostHalfLife
+	if postHalfLife <= 0 {
+		postHalfLife = defaultPostHalfLife
+	}
+	circleHalfLife := cfg.CircleHalfLife
+	if circleHalfLife <= 0 {
+		circleHalfLife = defaultCircleHalfLife
+	}
+	if cfg.Post == (conf.HotScorePost{}) {
+		cfg.Post = defaultPostWeights
+	}
+	if cfg.Circle == (conf.HotScoreCircle{}) {
+		cfg.Circle = defaultCircleWeights
+	}
+
+	return model.HotScoreParams{
+		Since:              time.Now().AddDate(0, 0, -window),
+		PostHalfLife:       time.Duration(postHalfLife * float64(time.Hour)),
+		CircleHalfLife:     time.Duration(circleHalfLife * float64(time.Hour)),
+		PostView:           cfg.Post.View,
+		PostLike:           cfg.Post.Like,
+		PostComment:        cfg.Post.Comment,
+		PostCollect:        cfg.Post.Collect,
+		CircleNewPost:      cfg.Circle.NewPost,
+		CircleActiveMember: cfg.Circle.ActiveMember,
+		CircleMemberGrowth: cfg.Circle.MemberGrowth,
+	}
+}
+
+// refreshHotScores 执行一轮热度计算
+func refreshHotScores() {
+	start := time.Now()
+	params := hotScoreParams()
+
+	posts, err := model.RefreshPostHotScores(pgsql.DB, params)
+	if err != nil {
+		logger.Log.Error("Failed to refresh post hot scores: " + err.Error())
+	}
+	for _, s := range posts {
+		pendingPostHot[s.ID] = s.Hot
+	}
+	pendingPostHot = pushHot("post", elasticsearch.PostIndex(), pendingPostHot)
+
+	circles, err := model.RefreshCircleHotScores(pgsql.DB, params)
+	if err != nil {
+		logger.Log.Error("Failed to refresh circle hot scores: " + err.Error())
+	}
+	for _, s := range circles {
+		pendingCircleHot[s.ID] = s.Hot
+	}
+	pendingCircleHot = pushHot("circle", conf.Config.Elasticsearch.Index, pendingCircleHot)
+
+	logger.Log.Info(fmt.Sprintf("Hot scores refreshed: posts=%d, circles=%d, cost=%s",
+		len(posts), len(circles), time.Since(start)))
+}
+
+// pushHot 把热度变化的文档批量推送到 Elasticsearch，返回仍需重试的部分
+func pushHot(kind, index string, pending map[int64]int) map[int64]int {
+	if len(pending) == 0 || elasticsearch.Client == nil {
+		return pending
+	}
+
+	if err := elasticsearch.BulkUpdateHot(index, pending); err != nil {
+		logger.Log.Error(fmt.Sprintf("Failed to push %s hot scores to Elasticsearch: %s", kind, err.Error()))
+		return pending
+	}
+	return make(map[int64]int)
+}
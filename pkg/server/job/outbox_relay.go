@@ -0,0 +1,170 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"interestBar/pkg/server/storage/rabbitmq"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// outboxRelayInterval 发件箱扫描间隔
+	outboxRelayInterval = time.Second
+	// outboxBatchSize 每批处理的最大事件数
+	outboxBatchSize = 100
+	// outboxBaseBackoff 投递失败后的首次重试间隔，之后每次翻倍
+	outboxBaseBackoff = 2 * time.Second
+	// outboxMaxBackoff 投递失败后的最大重试间隔
+	outboxMaxBackoff = 5 * time.Minute
+	// outboxMaxAttempts 最多投递次数，超过后停止投递该事件（约1小时）
+	outboxMaxAttempts = 20
+	// outboxRetention 已投递事件的保留时长
+	outboxRetention = 7 * 24 * time.Hour
+	// outboxCleanupInterval 清理已投递事件的间隔
+	outboxCleanupInterval = time.Hour
+)
+
+// StartOutboxRelay 启动发件箱中继任务
+// 持续把发件箱中待投递的事件发布到 RabbitMQ：同一聚合的事件严格按写入顺序投递，
+// 某个事件投递失败时按指数退避重试，在它成功之前同一聚合的后续事件不会被投递；
+// 失败次数超过上限的事件停止投递，同一聚合的后续事件继续（订阅者按ID读取最新数据，跳过一个事件不会导致数据错乱）
+func StartOutboxRelay() {
+	logger.Log.Info("Outbox relay started")
+
+	ticker := time.NewTicker(outboxRelayInterval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for range ticker.C {
+		relayOutbox()
+
+		if time.Since(lastCleanup) >= outboxCleanupInterval {
+			cleanupOutbox()
+			lastCleanup = time.Now()
+		}
+	}
+}
+
+// relayOutbox 持有中继锁投递所有可投递的事件，直到某一批没有投递成功的事件为止
+// 未拿到锁说明其他实例正在投递，直接跳过本轮；RabbitMQ 断线期间跳过投递，事件留在发件箱中等待重连
+func relayOutbox() {
+	if !rabbitmq.IsReady() {
+		return
+	}
+
+	// 咨询锁是会话级的，加锁、投递和解锁需要使用同一个数据库连接
+	err := pgsql.DB.Connection(func(conn *gorm.DB) error {
+		locked, err := model.TryLockOutbox(conn)
+		if err != nil || !locked {
+			return err
+		}
+		defer func() {
+			if err := model.UnlockOutbox(conn); err != nil {
+				logger.Log.Error("Failed to unlock outbox: " + err.Error())
+			}
+		}()
+
+		for {
+			sent, err := relayOutboxBatch(conn)
+			if err != nil {
+				return err
+			}
+			if sent == 0 {
+				return nil
+			}
+		}
+	})
+	if errors.Is(err, rabbitmq.ErrNotConnected) {
+		logger.Log.Warn("RabbitMQ disconnected, outbox relay paused")
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to relay outbox events: " + err.Error())
+	}
+}
+
+// relayOutboxBatch 投递一批各聚合的队首事件，返回成功投递的数量
+// 每个事件的投递结果单独提交，投递期间不持有事务；投递成功但未来得及标记时事件会被再次投递，订阅者按事件ID去重
+// 投递过程中断线时返回 ErrNotConnected，未投递的事件不计失败次数
+func relayOutboxBatch(db *gorm.DB) (int, error) {
+	events, err := model.GetDueOutboxEvents(db, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range events {
+		e := &events[i]
+		if err := rabbitmq.Publish(outboxEnvelope(e)); err != nil {
+			if errors.Is(err, rabbitmq.ErrNotConnected) {
+				return sent, err
+			}
+			if err := recordOutboxFailure(db, e, err); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		if err := model.MarkOutboxEventSent(db, e.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// recordOutboxFailure 记录一次投递失败：未超过上限的按指数退避推迟重试，否则停止投递
+func recordOutboxFailure(db *gorm.DB, e *model.OutboxEvent, cause error) error {
+	attempts := e.Attempts + 1
+	if attempts >= outboxMaxAttempts {
+		logger.Log.Error(fmt.Sprintf("Outbox event parked after %d attempts: id=%d, type=%s, err=%s",
+			attempts, e.ID, e.EventType, cause.Error()))
+		return model.ParkOutboxEvent(db, e.ID, cause.Error())
+	}
+
+	backoff := outboxBackoff(attempts)
+	logger.Log.Warn(fmt.Sprintf("Failed to relay outbox event: id=%d, type=%s, attempts=%d, retry in %s, err=%s",
+		e.ID, e.EventType, attempts, backoff, cause.Error()))
+	return model.MarkOutboxEventFailed(db, e.ID, time.Now().Add(backoff), cause.Error())
+}
+
+// outboxEnvelope 把发件箱记录还原为事件信封，沿用写入时生成的事件ID以便订阅者去重
+func outboxEnvelope(e *model.OutboxEvent) *rabbitmq.Event {
+	return &rabbitmq.Event{
+		ID:         e.EventID,
+		Type:       e.EventType,
+		Version:    e.Version,
+		OccurredAt: e.OccurredAt,
+		Payload:    json.RawMessage(e.Payload),
+	}
+}
+
+// outboxBackoff 计算第 attempts 次失败后的重试间隔
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	return backoff
+}
+
+// cleanupOutbox 清理超过保留时长的已投递事件
+func cleanupOutbox() {
+	deleted, err := model.DeleteSentOutboxEvents(pgsql.DB, time.Now().Add(-outboxRetention))
+	if err != nil {
+		logger.Log.Error("Failed to clean up outbox events: " + err.Error())
+		return
+	}
+	if deleted > 0 {
+		logger.Log.Info(fmt.Sprintf("Outbox events cleaned up: %d", deleted))
+	}
+}
@@ -0,0 +1,111 @@
+package job
+
+import (
+	"fmt"
+	"interestBar/pkg/logger"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/storage/db/pgsql"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// postPublishInterval 定时发布扫描间隔
+	postPublishInterval = 30 * time.Second
+	// postPublishBatchSize 每次扫描处理的最大草稿数
+	postPublishBatchSize = 100
+)
+
+// StartPostPublishScheduler 启动草稿定时发布任务
+// 周期性扫描到期的草稿，按圈子设置进入审核或直接发布
+func StartPostPublishScheduler() {
+	logger.Log.Info("Post publish scheduler started")
+
+	ticker := time.NewTicker(postPublishInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		publishDueDrafts()
+	}
+}
+
+// publishDueDrafts 发布本轮扫描开始时已到期的草稿
+// 按 (publish_at, id) 游标分批获取，发布失败的草稿留到下一轮重试，不会在本轮被反复取出
+func publishDueDrafts() {
+	now := time.Now()
+	hasCursor := false
+	var cursorTime time.Time
+	var cursorID int64
+	for {
+		drafts, err := model.GetDueDrafts(pgsql.DB, now, hasCursor, cursorTime, cursorID, postPublishBatchSize)
+		if err != nil {
+			logger.Log.Error("Failed to get due drafts: " + err.Error())
+			return
+		}
+
+		for i := range drafts {
+			publishScheduledDraft(&drafts[i])
+		}
+
+		if len(drafts) < postPublishBatchSize {
+			return
+		}
+		last := drafts[len(drafts)-1]
+		hasCursor, cursorTime, cursorID = true, *last.PublishAt, last.ID
+	}
+}
+
+// publishScheduledDraft 发布单个定时草稿，作者已无法在圈子发帖时取消定时并通知作者
+func publishScheduledDraft(post *model.Post) {
+	circle, err := model.GetCircleByID(pgsql.DB, post.CircleID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.Log.Error(fmt.Sprintf("Failed to get circle for scheduled post: post_id=%d, err=%s", post.ID, err.Error()))
+		return
+	}
+	member, err := model.GetMember(pgsql.DB, post.CircleID, post.UserID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.Log.Error(fmt.Sprintf("Failed to get member for scheduled post: post_id=%d, err=%s", post.ID, err.Error()))
+		return
+	}
+
+	// 检查草稿是否仍可发布
+	var reason string
+	switch {
+	case strings.TrimSpace(post.Title) == "":
+		reason = "标题为空"
+	case circle == nil || circle.Status != model.CircleStatusNormal:
+		reason = "圈子不存在或已不可发帖"
+	case member == nil || !model.CanMemberPost(member):
+		reason = "你当前无法在该圈子发帖"
+	}
+	if reason != "" {
+		notification := &model.Notification{
+			UserID:    post.UserID,
+			Type:      model.NotificationTypePost,
+			Title:     "定时发布失败",
+			Content:   fmt.Sprintf("你的草稿「%s」未能按时发布：%s，已转为普通草稿", post.Title, reason),
+			CircleID:  post.CircleID,
+			RelatedID: post.ID,
+		}
+		if err := model.CancelScheduledPublish(pgsql.DB, post.ID, notification); err != nil {
+			logger.Log.Error(fmt.Sprintf("Failed to cancel scheduled publish: post_id=%d, err=%s", post.ID, err.Error()))
+		}
+		return
+	}
+
+	status := model.DecidePublishStatus(circle, member)
+	err = event.Save(pgsql.DB, func(tx *gorm.DB) error {
+		return model.PublishDraft(tx, post, status)
+	}, event.Post(event.PostCreated, post))
+	if err != nil {
+		if err != model.ErrPostNotDraft {
+			logger.Log.Error(fmt.Sprintf("Failed to publish scheduled post: post_id=%d, err=%s", post.ID, err.Error()))
+		}
+		return
+	}
+
+	logger.Log.Info(fmt.Sprintf("Scheduled post published: post_id=%d, status=%d", post.ID, status))
+}
@@ -32,6 +32,16 @@ const (
 	CategoryStatusEnabled  = 1 // 启用/显示
 )
 
+// GetCategoryByID 根据ID获取分类信息
+func GetCategoryByID(db *gorm.DB, categoryID int) (*Category, error) {
+	var category Category
+	err := db.Where("id = ? AND deleted = ?", categoryID, 0).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
 // GetActiveCategories 获取启用的顶级分类列表
 func GetActiveCategories(db *gorm.DB) ([]Category, error) {
 	var categories []Category
@@ -58,3 +68,16 @@ func GetAllActiveCategories(db *gorm.DB) ([]Category, error) {
 		Find(&categories).Error
 	return categories, err
 }
+
+// changeCategoryCircleCount 调整分类下的圈子数，categoryID 为 0（未分类）时不做处理
+func changeCategoryCircleCount(db *gorm.DB, categoryID int, delta int) error {
+	if categoryID == 0 || delta == 0 {
+		return nil
+	}
+	query := db.Model(&Category{}).Where("id = ?", categoryID)
+	if delta < 0 {
+		// 防止计数被减为负数
+		query = query.Where("circle_count >= ?", -delta)
+	}
+	return query.UpdateColumn("circle_count", gorm.Expr("circle_count + ?", delta)).Error
+}
@@ -22,6 +22,7 @@ type Circle struct {
 	PostCount   int       `json:"post_count" gorm:"column:post_count;default:0"`                   // 帖子数量
 	JoinType    int16     `json:"join_type" gorm:"column:join_type;type:smallint;default:0"`       // 加入方式
 	Status      int16     `json:"status" gorm:"column:status;type:smallint;default:1"`             // 状态
+	TrustedSkipReview int16 `json:"trusted_skip_review" gorm:"column:trusted_skip_review;type:smallint;default:0"` // 信任成员发帖免审
 	Deleted     int16     `json:"deleted" gorm:"column:deleted;type:smallint;default:0"`           // 逻辑删除
 	CreateTime  time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
 	UpdateTime  time.Time `json:"update_time" gorm:"column:update_time;autoUpdateTime"`
@@ -66,6 +67,28 @@ func GetCircleBySlug(db *gorm.DB, slug string) (*Circle, error) {
 	return &circle, nil
 }
 
+// GetCirclesByIDs 根据ID列表批量获取圈子信息
+func GetCirclesByIDs(db *gorm.DB, circleIDs []int64) ([]Circle, error) {
+	var circles []Circle
+	if len(circleIDs) == 0 {
+		return circles, nil
+	}
+	err := db.Where("id IN ? AND deleted = ?", circleIDs, 0).Find(&circles).Error
+	return circles, err
+}
+
+// GetHiddenCircleIDs 获取对用户不可见的圈子ID（用户未加入的私密圈子）
+// 已解散圈子的帖子由搜索索引订阅者从索引中删除，不需要在这里排除
+func GetHiddenCircleIDs(db *gorm.DB, userID int64) ([]int64, error) {
+	var circleIDs []int64
+	err := db.Model(&Circle{}).
+		Where("deleted = ? AND join_type = ? AND id NOT IN (?)", 0, CircleJoinTypePrivate,
+			db.Model(&CircleMember{}).Select("circle_id").
+				Where("user_id = ? AND status IN ?", userID, []int16{MemberStatusNormal, MemberStatusMuted})).
+		Pluck("id", &circleIDs).Error
+	return circleIDs, err
+}
+
 // GetCirclesByCategory 根据分类ID获取圈子列表
 func GetCirclesByCategory(db *gorm.DB, categoryID int, page, pageSize int) ([]Circle, int64, error) {
 	var circles []Circle
@@ -117,6 +140,79 @@ func CreateCircle(db *gorm.DB, circle *Circle) error {
 			return err
 		}
 
+		// 3. 更新分类下的圈子数
+		return changeCategoryCircleCount(tx, circle.CategoryID, 1)
+	})
+}
+
+// UpdateCircle 更新圈子资料（使用事务）
+// updates 为需要更新的列，分类变更时同步调整新旧分类的圈子数
+func UpdateCircle(db *gorm.DB, circle *Circle, updates map[string]interface{}) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		oldCategoryID := circle.CategoryID
+
+		// 1. 更新圈子资料
+		result := tx.Model(&Circle{}).Where("id = ? AND deleted = ?", circle.ID, 0).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// 2. 重新读取最新数据，供调用方同步到 Elasticsearch
+		if err := tx.Where("id = ?", circle.ID).First(circle).Error; err != nil {
+			return err
+		}
+
+		// 3. 分类变更时调整新旧分类的圈子数
+		if circle.CategoryID != oldCategoryID {
+			if err := changeCategoryCircleCount(tx, oldCategoryID, -1); err != nil {
+				return err
+			}
+			if err := changeCategoryCircleCount(tx, circle.CategoryID, 1); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
+
+// DissolveCircle 解散圈子（使用事务）
+// 逻辑删除圈子、更新分类圈子数、隐藏圈内帖子、取消待确认的圈主转让，并通知全体成员
+func DissolveCircle(db *gorm.DB, circleID int64, notification *Notification) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 逻辑删除圈子
+		var circle Circle
+		if err := tx.Where("id = ? AND deleted = ?", circleID, 0).First(&circle).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&Circle{}).Where("id = ? AND deleted = ?", circleID, 0).Update("deleted", 1)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := changeCategoryCircleCount(tx, circle.CategoryID, -1); err != nil {
+			return err
+		}
+
+		// 2. 隐藏圈内帖子
+		if err := tx.Model(&Post{}).Where("circle_id = ? AND deleted = ?", circleID, 0).
+			Update("deleted", 1).Error; err != nil {
+			return err
+		}
+
+		// 3. 取消待确认的圈主转让
+		if err := tx.Model(&CircleTransfer{}).
+			Where("circle_id = ? AND status = ?", circleID, CircleTransferStatusPending).
+			Update("status", CircleTransferStatusCanceled).Error; err != nil {
+			return err
+		}
+
+		// 4. 通知全体成员
+		return NotifyCircleMembers(tx, circleID, notification)
+	})
+}
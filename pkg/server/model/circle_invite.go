@@ -0,0 +1,176 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CircleInvite 圈子邀请码/邀请链接表
+type CircleInvite struct {
+	ID           int64      `json:"id" gorm:"primarykey;column:id"`
+	CircleID     int64      `json:"circle_id" gorm:"column:circle_id;not null"`            // 圈子ID
+	CreatorID    int64      `json:"creator_id" gorm:"column:creator_id;not null"`          // 创建人ID
+	Code         string     `json:"code" gorm:"column:code;type:varchar(32);not null"`     // 邀请码
+	TargetUserID int64      `json:"target_user_id" gorm:"column:target_user_id;default:0"` // 定向邀请用户ID，0=不限
+	MaxUses      int        `json:"max_uses" gorm:"column:max_uses;default:0"`             // 最大使用次数，0=不限
+	UsedCount    int        `json:"used_count" gorm:"column:used_count;default:0"`         // 已使用次数
+	ExpireTime   *time.Time `json:"expire_time,omitempty" gorm:"column:expire_time"`       // 过期时间，NULL=永不过期
+	Status       int16      `json:"status" gorm:"column:status;type:smallint;default:1"`   // 状态
+	CreateTime   time.Time  `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+	UpdateTime   time.Time  `json:"update_time" gorm:"column:update_time;autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (CircleInvite) TableName() string {
+	return "circle_invite"
+}
+
+// CircleInviteRecord 圈子邀请使用流水表
+type CircleInviteRecord struct {
+	ID         int64     `json:"id" gorm:"primarykey;column:id"`
+	InviteID   int64     `json:"invite_id" gorm:"column:invite_id;not null"` // 邀请ID
+	CircleID   int64     `json:"circle_id" gorm:"column:circle_id;not null"` // 圈子ID
+	UserID     int64     `json:"user_id" gorm:"column:user_id;not null"`     // 使用邀请的用户ID
+	CreateTime time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (CircleInviteRecord) TableName() string {
+	return "circle_invite_record"
+}
+
+// CircleInviteStatus 邀请状态常量
+const (
+	CircleInviteStatusRevoked = 0 // 已撤销
+	CircleInviteStatusActive  = 1 // 有效
+)
+
+// ErrInviteUnavailable 邀请已撤销、过期或使用次数已满
+var ErrInviteUnavailable = errors.New("invite is no longer available")
+
+// IsExpired 检查邀请是否已过期
+func (i *CircleInvite) IsExpired() bool {
+	return i.ExpireTime != nil && !i.ExpireTime.After(time.Now())
+}
+
+// IsExhausted 检查邀请使用次数是否已满
+func (i *CircleInvite) IsExhausted() bool {
+	return i.MaxUses > 0 && i.UsedCount >= i.MaxUses
+}
+
+// GetInviteByID 根据ID获取邀请
+func GetInviteByID(db *gorm.DB, inviteID int64) (*CircleInvite, error) {
+	var invite CircleInvite
+	err := db.Where("id = ?", inviteID).First(&invite).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// GetInviteByCode 根据邀请码获取邀请
+func GetInviteByCode(db *gorm.DB, code string) (*CircleInvite, error) {
+	var invite CircleInvite
+	err := db.Where("code = ?", code).First(&invite).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// GetInvitesByCircle 获取圈子的邀请列表
+func GetInvitesByCircle(db *gorm.DB, circleID int64, page, pageSize int) ([]CircleInvite, int64, error) {
+	var invites []CircleInvite
+	var total int64
+
+	query := db.Model(&CircleInvite{}).Where("circle_id = ?", circleID)
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("create_time DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&invites).Error
+
+	return invites, total, err
+}
+
+// GetInviteRecords 获取邀请的使用记录
+func GetInviteRecords(db *gorm.DB, inviteID int64, page, pageSize int) ([]CircleInviteRecord, int64, error) {
+	var records []CircleInviteRecord
+	var total int64
+
+	query := db.Model(&CircleInviteRecord{}).Where("invite_id = ?", inviteID)
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("create_time DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&records).Error
+
+	return records, total, err
+}
+
+// CreateInvite 创建邀请
+func CreateInvite(db *gorm.DB, invite *CircleInvite) error {
+	return db.Create(invite).Error
+}
+
+// RevokeInvite 撤销邀请
+func RevokeInvite(db *gorm.DB, inviteID int64) error {
+	return db.Model(&CircleInvite{}).
+		Where("id = ?", inviteID).
+		Update("status", CircleInviteStatusRevoked).Error
+}
+
+// RedeemInvite 使用邀请加入圈子（使用事务）
+// 占用使用次数、记录流水、创建成员记录在同一事务中完成，私密圈子也可通过邀请加入
+func RedeemInvite(db *gorm.DB, invite *CircleInvite, userID int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 原子占用一次使用次数，并发兑换时不会超出 max_uses
+		result := tx.Model(&CircleInvite{}).
+			Where("id = ? AND status = ? AND (max_uses = 0 OR used_count < max_uses) AND (expire_time IS NULL OR expire_time > ?)",
+				invite.ID, CircleInviteStatusActive, time.Now()).
+			UpdateColumn("used_count", gorm.Expr("used_count + ?", 1))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInviteUnavailable
+		}
+
+		// 2. 记录使用流水
+		record := CircleInviteRecord{
+			InviteID: invite.ID,
+			CircleID: invite.CircleID,
+			UserID:   userID,
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+
+		// 3. 邀请优先于入圈申请，清理尚未审核的申请记录
+		if err := tx.Where("circle_id = ? AND user_id = ? AND status = ?", invite.CircleID, userID, MemberStatusPending).
+			Delete(&CircleMember{}).Error; err != nil {
+			return err
+		}
+
+		// 4. 创建成员记录并更新圈子成员数
+		member := CircleMember{
+			CircleID:  invite.CircleID,
+			UserID:    userID,
+			Role:      MemberRoleMember,
+			Status:    MemberStatusNormal,
+			IsTop:     0,
+			IsDisturb: 0,
+		}
+		return CreateMember(tx, &member)
+	})
+}
@@ -16,6 +16,8 @@ type CircleMember struct {
 	MuteEndTime  *time.Time `json:"mute_end_time,omitempty" gorm:"column:mute_end_time"`        // 禁言结束时间
 	IsTop        int16      `json:"is_top" gorm:"column:is_top;type:smallint;default:0"`        // 是否置顶显示
 	IsDisturb    int16      `json:"is_disturb" gorm:"column:is_disturb;type:smallint;default:0"` // 消息免打扰
+	ApplyMessage string     `json:"apply_message,omitempty" gorm:"column:apply_message;type:varchar(200);default:''"` // 入圈申请留言
+	IsTrusted    int16      `json:"is_trusted" gorm:"column:is_trusted;type:smallint;default:0"` // 是否为信任成员
 	CreateTime   time.Time  `json:"create_time" gorm:"column:create_time;autoCreateTime"`
 	UpdateTime   time.Time  `json:"update_time" gorm:"column:update_time;autoUpdateTime"`
 }
@@ -50,10 +52,10 @@ func GetMember(db *gorm.DB, circleID, userID int64) (*CircleMember, error) {
 	return &member, nil
 }
 
-// GetCirclesByUserID 获取用户加入的圈子列表
+// GetCirclesByUserID 获取用户加入的圈子列表（被禁言的成员仍属于圈子）
 func GetCirclesByUserID(db *gorm.DB, userID int64) ([]CircleMember, error) {
 	var members []CircleMember
-	err := db.Where("user_id = ? AND status = ?", userID, MemberStatusNormal).
+	err := db.Where("user_id = ? AND status IN ?", userID, []int16{MemberStatusNormal, MemberStatusMuted}).
 		Order("create_time DESC").
 		Find(&members).Error
 	return members, err
@@ -64,7 +66,8 @@ func GetMembersByCircleID(db *gorm.DB, circleID int64, role int16, page, pageSiz
 	var members []CircleMember
 	var total int64
 
-	query := db.Model(&CircleMember{}).Where("circle_id = ?", circleID)
+	// 待审核的申请不属于成员，单独通过 GetPendingMembers 查询
+	query := db.Model(&CircleMember{}).Where("circle_id = ? AND status <> ?", circleID, MemberStatusPending)
 
 	if role > 0 {
 		query = query.Where("role = ?", role)
@@ -121,3 +124,227 @@ func IsOwner(db *gorm.DB, circleID, userID int64) (bool, error) {
 	}
 	return member.Role == MemberRoleOwner, nil
 }
+
+// isCountedMember 判断该状态的成员是否计入圈子成员数（待审核和拉黑的不计入）
+func isCountedMember(status int16) bool {
+	return status == MemberStatusNormal || status == MemberStatusMuted
+}
+
+// GetPendingMembers 获取圈子的待审核入圈申请列表
+func GetPendingMembers(db *gorm.DB, circleID int64, page, pageSize int) ([]CircleMember, int64, error) {
+	var members []CircleMember
+	var total int64
+
+	query := db.Model(&CircleMember{}).Where("circle_id = ? AND status = ?", circleID, MemberStatusPending)
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询，先申请的先处理
+	err := query.Order("create_time ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&members).Error
+
+	return members, total, err
+}
+
+// CreateMember 添加成员记录，计入成员数的状态会同步更新圈子成员数（使用事务）
+func CreateMember(db *gorm.DB, member *CircleMember) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		status := member.Status
+
+		// 1. 插入 circle_member 表
+		if err := tx.Create(member).Error; err != nil {
+			return err
+		}
+
+		// status 字段带有默认值，零值(待审核)会被 GORM 替换为默认值，需要回写
+		if member.Status != status {
+			if err := tx.Model(member).UpdateColumn("status", status).Error; err != nil {
+				return err
+			}
+		}
+
+		if !isCountedMember(status) {
+			return nil
+		}
+
+		// 2. 更新圈子的成员计数
+		return tx.Model(&Circle{}).Where("id = ?", member.CircleID).
+			UpdateColumn("member_count", gorm.Expr("member_count + ?", 1)).Error
+	})
+}
+
+// ApproveMember 通过入圈申请并更新圈子成员数（使用事务）
+func ApproveMember(db *gorm.DB, circleID, userID int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 仅处理待审核的申请，避免重复审批导致计数错误
+		result := tx.Model(&CircleMember{}).
+			Where("circle_id = ? AND user_id = ? AND status = ?", circleID, userID, MemberStatusPending).
+			Update("status", MemberStatusNormal)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// 2. 更新圈子的成员计数
+		return tx.Model(&Circle{}).Where("id = ?", circleID).
+			UpdateColumn("member_count", gorm.Expr("member_count + ?", 1)).Error
+	})
+}
+
+// RejectMember 拒绝入圈申请（删除申请记录，用户可再次申请）
+func RejectMember(db *gorm.DB, circleID, userID int64) error {
+	result := db.Where("circle_id = ? AND user_id = ? AND status = ?", circleID, userID, MemberStatusPending).
+		Delete(&CircleMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RemoveMember 删除成员记录（退出圈子/撤回申请），并同步更新圈子成员数（使用事务）
+func RemoveMember(db *gorm.DB, member *CircleMember) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 删除 circle_member 记录
+		result := tx.Where("id = ? AND status = ?", member.ID, member.Status).Delete(&CircleMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if !isCountedMember(member.Status) {
+			return nil
+		}
+
+		// 2. 更新圈子的成员计数
+		return tx.Model(&Circle{}).Where("id = ? AND member_count > ?", member.CircleID, 0).
+			UpdateColumn("member_count", gorm.Expr("member_count - ?", 1)).Error
+	})
+}
+
+// ChangeMemberRole 变更成员角色并记录管理日志（使用事务）
+func ChangeMemberRole(db *gorm.DB, member *CircleMember, role int16, log *CircleMemberLog) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 以当前角色为条件更新，避免并发操作互相覆盖
+		result := tx.Model(&CircleMember{}).
+			Where("id = ? AND role = ?", member.ID, member.Role).
+			Update("role", role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// 2. 记录管理日志
+		return tx.Create(log).Error
+	})
+}
+
+// ChangeMemberStatus 变更成员状态并记录管理日志，同步调整圈子成员数（使用事务）
+func ChangeMemberStatus(db *gorm.DB, member *CircleMember, status int16, muteEndTime *time.Time, log *CircleMemberLog) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 以当前状态为条件更新，避免并发操作导致计数错误
+		result := tx.Model(&CircleMember{}).
+			Where("id = ? AND status = ?", member.ID, member.Status).
+			Updates(map[string]interface{}{
+				"status":        status,
+				"mute_end_time": muteEndTime,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// 2. 拉黑/解除拉黑会改变是否计入成员数
+		var delta int
+		switch {
+		case isCountedMember(member.Status) && !isCountedMember(status):
+			delta = -1
+		case !isCountedMember(member.Status) && isCountedMember(status):
+			delta = 1
+		}
+		if delta != 0 {
+			if err := tx.Model(&Circle{}).Where("id = ?", member.CircleID).
+				UpdateColumn("member_count", gorm.Expr("member_count + ?", delta)).Error; err != nil {
+				return err
+			}
+		}
+
+		// 3. 记录管理日志
+		return tx.Create(log).Error
+	})
+}
+
+// SetMemberTrusted 设置/取消信任成员并记录管理日志（使用事务）
+func SetMemberTrusted(db *gorm.DB, member *CircleMember, trusted bool, log *CircleMemberLog) error {
+	var value int16
+	if trusted {
+		value = 1
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&CircleMember{}).
+			Where("id = ? AND is_trusted = ?", member.ID, member.IsTrusted).
+			Update("is_trusted", value)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(log).Error
+	})
+}
+
+// UpdateMemberSettings 更新成员的个人设置（置顶显示、消息免打扰）
+func UpdateMemberSettings(db *gorm.DB, memberID int64, updates map[string]interface{}) error {
+	return db.Model(&CircleMember{}).Where("id = ?", memberID).Updates(updates).Error
+}
+
+// CanMemberPost 判断成员当前是否可以发帖（正常状态，或禁言已到期）
+func CanMemberPost(member *CircleMember) bool {
+	switch member.Status {
+	case MemberStatusNormal:
+		return true
+	case MemberStatusMuted:
+		return member.MuteEndTime == nil || !member.MuteEndTime.After(time.Now())
+	default:
+		return false
+	}
+}
+
+// IsTrustedMember 判断成员是否为信任成员（管理员、圈主以及被标记为信任的成员）
+func IsTrustedMember(member *CircleMember) bool {
+	return member.Status == MemberStatusNormal && (member.Role >= MemberRoleAdmin || member.IsTrusted == 1)
+}
+
+// GetAdminCircleIDs 获取用户担任圈主或管理员的圈子ID列表
+func GetAdminCircleIDs(db *gorm.DB, userID int64) ([]int64, error) {
+	var circleIDs []int64
+	err := db.Model(&CircleMember{}).
+		Where("user_id = ? AND role >= ? AND status = ?", userID, MemberRoleAdmin, MemberStatusNormal).
+		Pluck("circle_id", &circleIDs).Error
+	return circleIDs, err
+}
+
+// KickMember 踢出成员并记录管理日志（删除成员记录，用户可重新加入）
+func KickMember(db *gorm.DB, member *CircleMember, log *CircleMemberLog) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := RemoveMember(tx, member); err != nil {
+			return err
+		}
+		return tx.Create(log).Error
+	})
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CircleMemberLog 圈子成员管理操作日志表
+type CircleMemberLog struct {
+	ID           int64      `json:"id" gorm:"primarykey;column:id"`
+	CircleID     int64      `json:"circle_id" gorm:"column:circle_id;not null"`           // 圈子ID
+	OperatorID   int64      `json:"operator_id" gorm:"column:operator_id;not null"`       // 操作人ID
+	TargetUserID int64      `json:"target_user_id" gorm:"column:target_user_id;not null"` // 被操作的成员用户ID
+	Action       int16      `json:"action" gorm:"column:action;type:smallint;not null"`   // 操作类型
+	Reason       string     `json:"reason" gorm:"column:reason;type:varchar(500)"`        // 操作理由
+	MuteEndTime  *time.Time `json:"mute_end_time,omitempty" gorm:"column:mute_end_time"`  // 禁言截止时间
+	CreateTime   time.Time  `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (CircleMemberLog) TableName() string {
+	return "circle_member_log"
+}
+
+// MemberAction 成员管理操作类型常量
+const (
+	MemberActionPromote  = 1 // 设为管理员
+	MemberActionDemote   = 2 // 取消管理员
+	MemberActionMute     = 3 // 禁言
+	MemberActionBan      = 4 // 拉黑
+	MemberActionKick     = 5 // 踢出
+	MemberActionRestore  = 6 // 解除禁言/拉黑
+	MemberActionTransfer = 7 // 转让圈主
+	MemberActionTrust    = 8 // 设为信任成员
+	MemberActionUntrust  = 9 // 取消信任成员
+)
+
+// GetMemberLogs 获取圈子的成员管理日志，targetUserID 为 0 时查询全部成员
+func GetMemberLogs(db *gorm.DB, circleID, targetUserID int64, page, pageSize int) ([]CircleMemberLog, int64, error) {
+	var logs []CircleMemberLog
+	var total int64
+
+	query := db.Model(&CircleMemberLog{}).Where("circle_id = ?", circleID)
+
+	if targetUserID > 0 {
+		query = query.Where("target_user_id = ?", targetUserID)
+	}
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("create_time DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&logs).Error
+
+	return logs, total, err
+}
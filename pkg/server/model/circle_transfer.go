@@ -0,0 +1,161 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CircleTransfer 圈主转让记录表
+type CircleTransfer struct {
+	ID         int64     `json:"id" gorm:"primarykey;column:id"`
+	CircleID   int64     `json:"circle_id" gorm:"column:circle_id;not null"`          // 圈子ID
+	FromUserID int64     `json:"from_user_id" gorm:"column:from_user_id;not null"`    // 发起转让的圈主ID
+	ToUserID   int64     `json:"to_user_id" gorm:"column:to_user_id;not null"`        // 接收转让的成员ID
+	Status     int16     `json:"status" gorm:"column:status;type:smallint;default:0"` // 状态
+	ExpireTime time.Time `json:"expire_time" gorm:"column:expire_time;not null"`      // 确认截止时间
+	CreateTime time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+	UpdateTime time.Time `json:"update_time" gorm:"column:update_time;autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (CircleTransfer) TableName() string {
+	return "circle_transfer"
+}
+
+// CircleTransferStatus 转让状态常量
+const (
+	CircleTransferStatusPending  = 0 // 待确认
+	CircleTransferStatusAccepted = 1 // 已接受
+	CircleTransferStatusDeclined = 2 // 已拒绝
+	CircleTransferStatusCanceled = 3 // 已取消
+)
+
+// CircleTransferExpire 转让确认有效期
+const CircleTransferExpire = 72 * time.Hour
+
+// ErrTransferUnavailable 转让已失效（已处理、已过期或双方成员关系已变化）
+var ErrTransferUnavailable = errors.New("transfer is no longer available")
+
+// GetTransferByID 根据ID获取转让记录
+func GetTransferByID(db *gorm.DB, transferID int64) (*CircleTransfer, error) {
+	var transfer CircleTransfer
+	err := db.Where("id = ?", transferID).First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// GetPendingTransferByCircle 获取圈子当前待确认的转让
+func GetPendingTransferByCircle(db *gorm.DB, circleID int64) (*CircleTransfer, error) {
+	var transfer CircleTransfer
+	err := db.Where("circle_id = ? AND status = ? AND expire_time > ?", circleID, CircleTransferStatusPending, time.Now()).
+		First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// GetPendingTransfersByUser 获取转让给用户且待确认的记录
+func GetPendingTransfersByUser(db *gorm.DB, userID int64) ([]CircleTransfer, error) {
+	var transfers []CircleTransfer
+	err := db.Where("to_user_id = ? AND status = ? AND expire_time > ?", userID, CircleTransferStatusPending, time.Now()).
+		Order("create_time DESC").
+		Find(&transfers).Error
+	return transfers, err
+}
+
+// CreateTransfer 发起圈主转让并通知接收人（使用事务）
+// 同一圈子只保留一条待确认的转让，新的转让会取消之前的
+func CreateTransfer(db *gorm.DB, transfer *CircleTransfer, notification *Notification) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 取消该圈子之前待确认的转让
+		if err := tx.Model(&CircleTransfer{}).
+			Where("circle_id = ? AND status = ?", transfer.CircleID, CircleTransferStatusPending).
+			Update("status", CircleTransferStatusCanceled).Error; err != nil {
+			return err
+		}
+
+		// 2. 插入转让记录
+		if err := tx.Create(transfer).Error; err != nil {
+			return err
+		}
+
+		// 3. 通知接收人
+		notification.RelatedID = transfer.ID
+		return tx.Create(notification).Error
+	})
+}
+
+// CloseTransfer 关闭待确认的转让（拒绝/取消）
+func CloseTransfer(db *gorm.DB, transferID int64, status int16) error {
+	result := db.Model(&CircleTransfer{}).
+		Where("id = ? AND status = ?", transferID, CircleTransferStatusPending).
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransferUnavailable
+	}
+	return nil
+}
+
+// AcceptTransfer 接受圈主转让（使用事务）
+// 原圈主降为管理员，接收人升为圈主，并更新圈子的创建人
+func AcceptTransfer(db *gorm.DB, transfer *CircleTransfer, log *CircleMemberLog, notification *Notification) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 标记转让已接受（仅限待确认且未过期）
+		result := tx.Model(&CircleTransfer{}).
+			Where("id = ? AND status = ? AND expire_time > ?", transfer.ID, CircleTransferStatusPending, time.Now()).
+			Update("status", CircleTransferStatusAccepted)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTransferUnavailable
+		}
+
+		// 2. 原圈主降为管理员
+		result = tx.Model(&CircleMember{}).
+			Where("circle_id = ? AND user_id = ? AND role = ?", transfer.CircleID, transfer.FromUserID, MemberRoleOwner).
+			Update("role", MemberRoleAdmin)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTransferUnavailable
+		}
+
+		// 3. 接收人升为圈主（必须仍是正常状态的成员）
+		result = tx.Model(&CircleMember{}).
+			Where("circle_id = ? AND user_id = ? AND status = ?", transfer.CircleID, transfer.ToUserID, MemberStatusNormal).
+			Update("role", MemberRoleOwner)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTransferUnavailable
+		}
+
+		// 4. 更新圈子创建人
+		result = tx.Model(&Circle{}).
+			Where("id = ? AND creator_id = ? AND deleted = ?", transfer.CircleID, transfer.FromUserID, 0).
+			Update("creator_id", transfer.ToUserID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTransferUnavailable
+		}
+
+		// 5. 记录管理日志并通知原圈主
+		if err := tx.Create(log).Error; err != nil {
+			return err
+		}
+		return tx.Create(notification).Error
+	})
+}
@@ -0,0 +1,267 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CollectFolder 收藏夹表
+type CollectFolder struct {
+	ID          int64     `json:"id" gorm:"primarykey;column:id"`
+	UserID      int64     `json:"user_id" gorm:"column:user_id;not null"`                    // 所属用户ID
+	Name        string    `json:"name" gorm:"column:name;type:varchar(50);not null"`         // 收藏夹名称
+	Description string    `json:"description" gorm:"column:description;type:varchar(200)"`   // 描述
+	IsPublic    int16     `json:"is_public" gorm:"column:is_public;type:smallint;default:0"` // 是否公开：0=私密, 1=公开
+	ItemCount   int       `json:"item_count" gorm:"column:item_count;default:0"`             // 收藏的帖子数
+	CreateTime  time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+	UpdateTime  time.Time `json:"update_time" gorm:"column:update_time;autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (CollectFolder) TableName() string {
+	return "collect_folder"
+}
+
+// PostCollect 帖子收藏表（每个用户对每个帖子只有一条收藏记录，归属于一个收藏夹）
+type PostCollect struct {
+	ID         int64     `json:"id" gorm:"primarykey;column:id"`
+	UserID     int64     `json:"user_id" gorm:"column:user_id;not null"`     // 收藏人
+	PostID     int64     `json:"post_id" gorm:"column:post_id;not null"`     // 被收藏的帖子
+	FolderID   int64     `json:"folder_id" gorm:"column:folder_id;not null"` // 所在收藏夹
+	CreateTime time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PostCollect) TableName() string {
+	return "post_collect"
+}
+
+// MaxCollectFolders 每个用户最多创建的收藏夹数量
+const MaxCollectFolders = 100
+
+var (
+	// ErrCollectFolderNameExists 收藏夹名称已存在
+	ErrCollectFolderNameExists = errors.New("collect folder name already exists")
+	// ErrCollectFolderLimit 收藏夹数量已达上限
+	ErrCollectFolderLimit = errors.New("collect folder limit reached")
+	// ErrPostAlreadyInFolder 帖子已在该收藏夹中
+	ErrPostAlreadyInFolder = errors.New("post already in this folder")
+)
+
+// GetCollectFolderByID 根据ID获取收藏夹
+func GetCollectFolderByID(db *gorm.DB, folderID int64) (*CollectFolder, error) {
+	var folder CollectFolder
+	err := db.Where("id = ?", folderID).First(&folder).Error
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// GetCollectFoldersByUser 获取用户的收藏夹列表，publicOnly 为 true 时只返回公开的收藏夹
+func GetCollectFoldersByUser(db *gorm.DB, userID int64, publicOnly bool) ([]CollectFolder, error) {
+	var folders []CollectFolder
+	query := db.Where("user_id = ?", userID)
+	if publicOnly {
+		query = query.Where("is_public = ?", 1)
+	}
+	err := query.Order("create_time ASC, id ASC").Find(&folders).Error
+	return folders, err
+}
+
+// isCollectFolderNameTaken 检查用户是否已有同名收藏夹
+func isCollectFolderNameTaken(db *gorm.DB, userID int64, name string, excludeID int64) (bool, error) {
+	var count int64
+	err := db.Model(&CollectFolder{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreateCollectFolder 创建收藏夹
+func CreateCollectFolder(db *gorm.DB, folder *CollectFolder) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&CollectFolder{}).Where("user_id = ?", folder.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= MaxCollectFolders {
+			return ErrCollectFolderLimit
+		}
+
+		taken, err := isCollectFolderNameTaken(tx, folder.UserID, folder.Name, 0)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrCollectFolderNameExists
+		}
+
+		isPublic := folder.IsPublic
+		if err := tx.Create(folder).Error; err != nil {
+			return err
+		}
+		// is_public 字段带有默认值，零值(私密)会被 GORM 替换为默认值，需要回写
+		if folder.IsPublic != isPublic {
+			if err := tx.Model(folder).UpdateColumn("is_public", isPublic).Error; err != nil {
+				return err
+			}
+			folder.IsPublic = isPublic
+		}
+		return nil
+	})
+}
+
+// UpdateCollectFolder 更新收藏夹（重命名、修改描述和公开状态）
+func UpdateCollectFolder(db *gorm.DB, folder *CollectFolder, updates map[string]interface{}) error {
+	if name, ok := updates["name"].(string); ok {
+		taken, err := isCollectFolderNameTaken(db, folder.UserID, name, folder.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrCollectFolderNameExists
+		}
+	}
+	return db.Model(folder).Updates(updates).Error
+}
+
+// DeleteCollectFolder 删除收藏夹及其中的所有收藏（使用事务），同步扣减相关帖子的收藏数
+func DeleteCollectFolder(db *gorm.DB, folderID int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 扣减收藏夹中所有帖子的收藏数
+		if err := tx.Model(&Post{}).
+			Where("id IN (?)", tx.Model(&PostCollect{}).Select("post_id").Where("folder_id = ?", folderID)).
+			UpdateColumn("collect_count", gorm.Expr("GREATEST(collect_count - ?, 0)", 1)).Error; err != nil {
+			return err
+		}
+
+		// 2. 删除收藏记录
+		if err := tx.Where("folder_id = ?", folderID).Delete(&PostCollect{}).Error; err != nil {
+			return err
+		}
+
+		// 3. 删除收藏夹
+		result := tx.Where("id = ?", folderID).Delete(&CollectFolder{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// GetPostCollect 获取用户对帖子的收藏记录
+func GetPostCollect(db *gorm.DB, userID, postID int64) (*PostCollect, error) {
+	var collect PostCollect
+	err := db.Where("user_id = ? AND post_id = ?", userID, postID).First(&collect).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collect, nil
+}
+
+// CollectPost 收藏帖子到收藏夹（使用事务）
+// 首次收藏时增加帖子收藏数；帖子已在其他收藏夹中时移动到目标收藏夹，不重复计数
+func CollectPost(db *gorm.DB, userID, postID, folderID int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 插入或移动收藏记录，依赖 (user_id, post_id) 唯一索引
+		var previous []int64
+		if err := tx.Raw(`SELECT folder_id FROM post_collect WHERE user_id = ? AND post_id = ? FOR UPDATE`,
+			userID, postID).Scan(&previous).Error; err != nil {
+			return err
+		}
+
+		if len(previous) > 0 {
+			if previous[0] == folderID {
+				return ErrPostAlreadyInFolder
+			}
+			if err := tx.Model(&PostCollect{}).Where("user_id = ? AND post_id = ?", userID, postID).
+				Updates(map[string]interface{}{"folder_id": folderID, "create_time": time.Now()}).Error; err != nil {
+				return err
+			}
+			if err := changeCollectFolderItemCount(tx, previous[0], -1); err != nil {
+				return err
+			}
+			return changeCollectFolderItemCount(tx, folderID, 1)
+		}
+
+		result := tx.Exec(`INSERT INTO post_collect (user_id, post_id, folder_id, create_time)
+			VALUES (?, ?, ?, ?) ON CONFLICT (user_id, post_id) DO NOTHING`,
+			userID, postID, folderID, time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// 并发的另一次收藏已经成功，视为已收藏
+			return ErrPostAlreadyInFolder
+		}
+
+		// 2. 更新收藏夹和帖子的计数
+		if err := changeCollectFolderItemCount(tx, folderID, 1); err != nil {
+			return err
+		}
+		return tx.Model(&Post{}).Where("id = ?", postID).
+			UpdateColumn("collect_count", gorm.Expr("collect_count + ?", 1)).Error
+	})
+}
+
+// UncollectPost 取消收藏帖子（使用事务），返回是否确实取消了收藏
+func UncollectPost(db *gorm.DB, userID, postID int64) (bool, error) {
+	removed := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var folderIDs []int64
+		if err := tx.Raw(`DELETE FROM post_collect WHERE user_id = ? AND post_id = ? RETURNING folder_id`,
+			userID, postID).Scan(&folderIDs).Error; err != nil {
+			return err
+		}
+		if len(folderIDs) == 0 {
+			return nil
+		}
+		removed = true
+
+		if err := changeCollectFolderItemCount(tx, folderIDs[0], -1); err != nil {
+			return err
+		}
+		return tx.Model(&Post{}).Where("id = ?", postID).
+			UpdateColumn("collect_count", gorm.Expr("GREATEST(collect_count - ?, 0)", 1)).Error
+	})
+	return removed, err
+}
+
+// GetCollectedPosts 获取收藏夹中当前用户有权查看的帖子（按收藏时间倒序）
+// 已删除、未发布以及所在私密圈子对查看者不可见的帖子不展示
+func GetCollectedPosts(db *gorm.DB, folderID, viewerID int64, page, pageSize int) ([]Post, int64, error) {
+	var posts []Post
+	var total int64
+
+	query := db.Table("post_collect AS pc").
+		Joins("JOIN post ON post.id = pc.post_id").
+		Where("pc.folder_id = ? AND post.status = ? AND post.deleted = ?", folderID, PostStatusPublished, 0).
+		Where("post.circle_id NOT IN (?) OR post.circle_id IN (?)",
+			db.Model(&Circle{}).Select("id").Where("join_type = ?", CircleJoinTypePrivate),
+			db.Model(&CircleMember{}).Select("circle_id").
+				Where("user_id = ? AND status IN ?", viewerID, []int16{MemberStatusNormal, MemberStatusMuted}))
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Select("post.*").
+		Order("pc.create_time DESC, pc.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&posts).Error
+
+	return posts, total, err
+}
+
+// changeCollectFolderItemCount 调整收藏夹的帖子数，不会减为负数
+func changeCollectFolderItemCount(db *gorm.DB, folderID int64, delta int) error {
+	return db.Model(&CollectFolder{}).Where("id = ?", folderID).
+		UpdateColumn("item_count", gorm.Expr("GREATEST(item_count + ?, 0)", delta)).Error
+}
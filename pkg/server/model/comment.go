@@ -1,19 +1,20 @@
 package model
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// Comment 评论表
+// Comment 评论索引表（只存放轻量元数据，评论正文见 CommentContent）
 type Comment struct {
 	ID          int64      `json:"id" gorm:"primarykey;column:id"`
 	PostID      int64      `json:"post_id" gorm:"column:post_id;not null"`                // 所属帖子ID
 	UserID      int64      `json:"user_id" gorm:"column:user_id;not null"`                // 评论发布者ID
 	RootID      int64      `json:"root_id" gorm:"column:root_id;default:0"`                // 根评论ID，0为根评论
 	ReplyToID   int64      `json:"reply_to_id" gorm:"column:reply_to_id;default:0"`        // 被回复的评论ID，0为非回复
-	Content     string     `json:"content" gorm:"column:content;type:text;not null"`       // 评论内容
 	LikeCount   int        `json:"like_count" gorm:"column:like_count;default:0"`          // 点赞数
 	ReplyCount  int        `json:"reply_count" gorm:"column:reply_count;default:0"`        // 子评论数
 	Status      int16      `json:"status" gorm:"column:status;type:smallint;default:1"`    // 状态
@@ -24,7 +25,7 @@ type Comment struct {
 
 // TableName 指定表名
 func (Comment) TableName() string {
-	return "comment"
+	return "comment_index"
 }
 
 // CommentStatus 评论状态常量
@@ -34,6 +35,32 @@ const (
 	CommentStatusHidden   = 3 // 折叠/隐藏
 )
 
+// ErrCommentStatusUnchanged 评论已处于目标状态
+var ErrCommentStatusUnchanged = errors.New("comment status unchanged")
+
+// CommentViewer 评论列表的查看者，决定能看到哪些非正常状态的评论
+type CommentViewer struct {
+	UserID    int64 // 当前用户ID，作者本人可以看到自己被折叠或审核中的评论
+	Moderator bool  // 是否为圈子管理员，管理员可以看到审核中的评论
+}
+
+// scopeVisibleComments 按查看者过滤评论状态
+// 正常评论所有人可见；被折叠的评论只在折叠区展示，作者本人在列表中也能看到；审核中的评论仅作者和管理员可见
+func scopeVisibleComments(query *gorm.DB, viewer CommentViewer) *gorm.DB {
+	if viewer.Moderator {
+		return query.Where("(status IN ? OR user_id = ?)", []int16{CommentStatusNormal, CommentStatusReview}, viewer.UserID)
+	}
+	return query.Where("(status = ? OR user_id = ?)", CommentStatusNormal, viewer.UserID)
+}
+
+// IsCommentVisible 判断评论对查看者是否可见，规则同 scopeVisibleComments
+func IsCommentVisible(db *gorm.DB, commentID int64, viewer CommentViewer) (bool, error) {
+	var count int64
+	query := db.Model(&Comment{}).Where("id = ? AND deleted = ?", commentID, 0)
+	err := scopeVisibleComments(query, viewer).Count(&count).Error
+	return count > 0, err
+}
+
 // GetCommentByID 根据ID获取评论
 func GetCommentByID(db *gorm.DB, commentID int64) (*Comment, error) {
 	var comment Comment
@@ -44,42 +71,94 @@ func GetCommentByID(db *gorm.DB, commentID int64) (*Comment, error) {
 	return &comment, nil
 }
 
-// GetRootCommentsByPost 获取帖子的顶级评论列表
-func GetRootCommentsByPost(db *gorm.DB, postID int64, page, pageSize int) ([]Comment, int64, error) {
-	var comments []Comment
-	var total int64
-
-	query := db.Model(&Comment{}).Where("post_id = ? AND root_id = ? AND deleted = ?", postID, 0, 0)
+// CommentSort 评论排序方式
+const (
+	CommentSortHot    = "hot"    // 最热：按点赞数倒序
+	CommentSortNewest = "newest" // 最新：按时间倒序
+	CommentSortOldest = "oldest" // 最早：按时间正序
+	CommentSortAuthor = "author" // 只看楼主：仅帖子作者的评论，按时间正序
+)
 
-	// 获取总数
-	query.Count(&total)
+// CommentCursor 评论游标分页的位置，即上一页最后一条评论的排序键
+type CommentCursor struct {
+	LikeCount  int       `json:"l,omitempty"` // 仅最热排序使用
+	CreateTime time.Time `json:"t"`
+	ID         int64     `json:"i"`
+}
 
-	// 分页查询，按点赞数和时间倒序
-	err := query.Order("like_count DESC, create_time DESC").
-		Offset((page - 1) * pageSize).
-		Limit(pageSize).
-		Find(&comments).Error
+// NewCommentCursor 以评论的排序键生成游标
+func NewCommentCursor(comment *Comment) CommentCursor {
+	return CommentCursor{
+		LikeCount:  comment.LikeCount,
+		CreateTime: comment.CreateTime,
+		ID:         comment.ID,
+	}
+}
 
-	return comments, total, err
+// CommentPageQuery 评论游标分页查询条件
+type CommentPageQuery struct {
+	Sort     string         // 排序方式，见 CommentSort 常量
+	AuthorID int64          // 只看楼主时的帖子作者ID
+	Cursor   *CommentCursor // 为 nil 时从第一条开始
+	Limit    int            // 每页数量
+	Viewer   CommentViewer  // 查看者
+	Folded   bool           // 是否查询折叠区（只返回被折叠的评论）
 }
 
-// GetSubCommentsByRoot 获取某条评论的子回复列表
-func GetSubCommentsByRoot(db *gorm.DB, rootID int64, page, pageSize int) ([]Comment, int64, error) {
-	var comments []Comment
-	var total int64
+// GetRootCommentsByPost 游标分页获取帖子的顶级评论列表，返回是否还有下一页
+// 最热排序走 idx_comment_post_root_like，其余排序走 idx_comment_post_root_time
+func GetRootCommentsByPost(db *gorm.DB, postID int64, q CommentPageQuery) ([]Comment, bool, error) {
+	query := db.Model(&Comment{}).Where("post_id = ? AND root_id = ? AND deleted = ?", postID, 0, 0)
+	return pageComments(query, q)
+}
 
+// GetSubCommentsByRoot 游标分页获取某条评论的子回复列表，返回是否还有下一页
+func GetSubCommentsByRoot(db *gorm.DB, rootID int64, q CommentPageQuery) ([]Comment, bool, error) {
 	query := db.Model(&Comment{}).Where("root_id = ? AND deleted = ?", rootID, 0)
+	return pageComments(query, q)
+}
 
-	// 获取总数
-	query.Count(&total)
+// pageComments 按排序方式追加游标条件和排序，多查一条用于判断是否还有下一页
+// 排序键都以 id 兜底，保证顺序稳定，翻页时不会因点赞数变化或时间相同而重复、遗漏
+func pageComments(query *gorm.DB, q CommentPageQuery) ([]Comment, bool, error) {
+	if q.Folded {
+		query = query.Where("status = ?", CommentStatusHidden)
+	} else {
+		query = scopeVisibleComments(query, q.Viewer)
+	}
 
-	// 分页查询，按时间正序
-	err := query.Order("create_time ASC").
-		Offset((page - 1) * pageSize).
-		Limit(pageSize).
-		Find(&comments).Error
+	cur := q.Cursor
+	switch q.Sort {
+	case CommentSortHot:
+		if cur != nil {
+			query = query.Where("(like_count, create_time, id) < (?, ?, ?)", cur.LikeCount, cur.CreateTime, cur.ID)
+		}
+		query = query.Order("like_count DESC, create_time DESC, id DESC")
+	case CommentSortNewest:
+		if cur != nil {
+			query = query.Where("(create_time, id) < (?, ?)", cur.CreateTime, cur.ID)
+		}
+		query = query.Order("create_time DESC, id DESC")
+	default:
+		if q.Sort == CommentSortAuthor {
+			query = query.Where("user_id = ?", q.AuthorID)
+		}
+		if cur != nil {
+			query = query.Where("(create_time, id) > (?, ?)", cur.CreateTime, cur.ID)
+		}
+		query = query.Order("create_time ASC, id ASC")
+	}
 
-	return comments, total, err
+	var comments []Comment
+	if err := query.Limit(q.Limit + 1).Find(&comments).Error; err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(comments) > q.Limit
+	if hasMore {
+		comments = comments[:q.Limit]
+	}
+	return comments, hasMore, nil
 }
 
 // GetCommentsByUser 获取用户的评论历史
@@ -113,6 +192,11 @@ func DecrementCommentLikeCount(db *gorm.DB, commentID int64) error {
 		UpdateColumn("like_count", gorm.Expr("like_count - ?", 1)).Error
 }
 
+// ApplyCommentLikeCountDeltas 批量写回评论点赞数增量（key 为评论ID，value 为增量）
+func ApplyCommentLikeCountDeltas(db *gorm.DB, deltas map[int64]int64) error {
+	return applyCountDeltas(db, "comment_index", "id", "like_count", deltas)
+}
+
 // IncrementReplyCount 增加回复数
 func IncrementReplyCount(db *gorm.DB, rootID int64) error {
 	return db.Model(&Comment{}).Where("id = ?", rootID).
@@ -124,3 +208,223 @@ func DecrementReplyCount(db *gorm.DB, rootID int64) error {
 	return db.Model(&Comment{}).Where("id = ?", rootID).
 		UpdateColumn("reply_count", gorm.Expr("reply_count - ?", 1)).Error
 }
+
+// GetCommentsByIDs 根据ID列表批量获取评论（不保证顺序）
+func GetCommentsByIDs(db *gorm.DB, commentIDs []int64) ([]Comment, error) {
+	var comments []Comment
+	if len(commentIDs) == 0 {
+		return comments, nil
+	}
+	err := db.Where("id IN ? AND deleted = ?", commentIDs, 0).Find(&comments).Error
+	return comments, err
+}
+
+// GetFirstRepliesByRoots 批量获取多条根评论中查看者可见的前 limit 条回复（按时间正序）
+// 使用窗口函数一次查询完成，避免对每条根评论单独查询
+func GetFirstRepliesByRoots(db *gorm.DB, rootIDs []int64, limit int, viewer CommentViewer) ([]Comment, error) {
+	var comments []Comment
+	if len(rootIDs) == 0 || limit <= 0 {
+		return comments, nil
+	}
+
+	ranked := scopeVisibleComments(db.Model(&Comment{}), viewer).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY root_id ORDER BY create_time ASC, id ASC) AS rn").
+		Where("root_id IN ? AND deleted = ?", rootIDs, 0)
+
+	err := db.Table("(?) AS t", ranked).
+		Where("rn <= ?", limit).
+		Order("root_id, create_time ASC, id ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+// CreateComment 发表评论或回复（使用事务）
+// 同时写入评论索引和评论内容，同步更新帖子的评论数和最后回复时间，回复时同步更新根评论的回复数
+func CreateComment(db *gorm.DB, comment *Comment, content *CommentContent) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 回复时先增加根评论的回复数，根评论已被删除或不是正常状态则不允许回复
+		if comment.RootID > 0 {
+			result := tx.Model(&Comment{}).Where("id = ? AND status = ? AND deleted = ?", comment.RootID, CommentStatusNormal, 0).
+				UpdateColumn("reply_count", gorm.Expr("reply_count + ?", 1))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		// 2. 插入评论索引，再以索引ID写入评论内容
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		content.CommentID = comment.ID
+		if err := tx.Create(content).Error; err != nil {
+			return err
+		}
+
+		// 3. 更新帖子的评论数和最后回复时间
+		return tx.Model(&Post{}).Where("id = ?", comment.PostID).
+			UpdateColumns(map[string]interface{}{
+				"comment_count":   gorm.Expr("comment_count + ?", 1),
+				"last_reply_time": comment.CreateTime,
+			}).Error
+	})
+}
+
+// DeleteComment 删除评论（使用事务）
+// 删除根评论时一并删除其下所有回复；根评论回复数只统计正常状态的回复，
+// 帖子评论数只统计正常状态且根评论也正常的评论，按实际删除的计入评论扣减
+func DeleteComment(db *gorm.DB, comment *Comment) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 锁定评论，以数据库中的最新状态为准
+		var current Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted = ?", comment.ID, 0).First(&current).Error; err != nil {
+			return err
+		}
+
+		// 2. 逻辑删除评论
+		if err := tx.Model(&Comment{}).Where("id = ?", current.ID).Update("deleted", 1).Error; err != nil {
+			return err
+		}
+		var removed int64
+		if current.RootID == 0 {
+			// 3. 根评论：一并删除所有回复，先删除正常状态的回复以统计扣减数
+			result := tx.Model(&Comment{}).
+				Where("root_id = ? AND status = ? AND deleted = ?", current.ID, CommentStatusNormal, 0).
+				Update("deleted", 1)
+			if result.Error != nil {
+				return result.Error
+			}
+			if err := tx.Model(&Comment{}).Where("root_id = ? AND deleted = ?", current.ID, 0).
+				Update("deleted", 1).Error; err != nil {
+				return err
+			}
+			// 根评论不是正常状态时，它和它的回复都没有计入帖子评论数
+			if current.Status == CommentStatusNormal {
+				removed = 1 + result.RowsAffected
+			}
+		} else if current.Status == CommentStatusNormal {
+			// 3. 正常状态的回复：扣减根评论的回复数，根评论正常时才计入了帖子评论数
+			if err := changeCommentReplyCount(tx, current.RootID, -1); err != nil {
+				return err
+			}
+			rootNormal, err := isRootCommentNormal(tx, current.RootID)
+			if err != nil {
+				return err
+			}
+			if rootNormal {
+				removed = 1
+			}
+		}
+
+		// 4. 扣减帖子的评论数
+		return changePostCommentCount(tx, current.PostID, -removed)
+	})
+}
+
+// RemoveComment 管理员删除评论并通知作者（使用事务）
+func RemoveComment(db *gorm.DB, comment *Comment, notification *Notification) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := DeleteComment(tx, comment); err != nil {
+			return err
+		}
+		if notification == nil {
+			return nil
+		}
+		return tx.Create(notification).Error
+	})
+}
+
+// SetCommentStatus 修改评论状态（折叠、送审、恢复），可选地通知作者（使用事务）
+// 评论在正常与非正常状态之间切换时，同步调整帖子评论数和根评论回复数，使计数只包含正常评论；
+// 根评论被折叠后其回复也随之隐藏，帖子评论数一并扣除它的正常回复，恢复时再加回
+func SetCommentStatus(db *gorm.DB, commentID int64, status int16, notification *Notification) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 锁定评论，以数据库中的最新状态为准
+		var current Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted = ?", commentID, 0).First(&current).Error; err != nil {
+			return err
+		}
+		if current.Status == status {
+			return ErrCommentStatusUnchanged
+		}
+
+		// 2. 更新状态
+		if err := tx.Model(&Comment{}).Where("id = ?", current.ID).Update("status", status).Error; err != nil {
+			return err
+		}
+
+		// 3. 调整计数
+		var delta int64
+		if current.Status == CommentStatusNormal {
+			delta = -1
+		} else if status == CommentStatusNormal {
+			delta = 1
+		}
+		if delta != 0 {
+			postDelta := delta
+			if current.RootID > 0 {
+				if err := changeCommentReplyCount(tx, current.RootID, delta); err != nil {
+					return err
+				}
+				// 根评论不是正常状态时，回复本来就没有计入帖子评论数
+				rootNormal, err := isRootCommentNormal(tx, current.RootID)
+				if err != nil {
+					return err
+				}
+				if !rootNormal {
+					postDelta = 0
+				}
+			} else {
+				var replies int64
+				if err := tx.Model(&Comment{}).
+					Where("root_id = ? AND status = ? AND deleted = ?", current.ID, CommentStatusNormal, 0).
+					Count(&replies).Error; err != nil {
+					return err
+				}
+				postDelta = delta * (1 + replies)
+			}
+			if err := changePostCommentCount(tx, current.PostID, postDelta); err != nil {
+				return err
+			}
+		}
+
+		// 4. 通知作者
+		if notification == nil {
+			return nil
+		}
+		return tx.Create(notification).Error
+	})
+}
+
+// isRootCommentNormal 判断根评论是否为正常状态，加共享锁避免与根评论的折叠、恢复并发而算错帖子评论数
+func isRootCommentNormal(tx *gorm.DB, rootID int64) (bool, error) {
+	var root Comment
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id", "status").
+		Where("id = ? AND deleted = ?", rootID, 0).First(&root).Error
+	if err == gorm.ErrRecordNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return root.Status == CommentStatusNormal, nil
+}
+
+// changeCommentReplyCount 调整根评论的回复数，不会减为负数
+func changeCommentReplyCount(db *gorm.DB, rootID int64, delta int64) error {
+	return db.Model(&Comment{}).Where("id = ?", rootID).
+		UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count + ?, 0)", delta)).Error
+}
+
+// changePostCommentCount 调整帖子的评论数，不会减为负数
+func changePostCommentCount(db *gorm.DB, postID int64, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+	return db.Model(&Post{}).Where("id = ?", postID).
+		UpdateColumn("comment_count", gorm.Expr("GREATEST(comment_count + ?, 0)", delta)).Error
+}
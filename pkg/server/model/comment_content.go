@@ -0,0 +1,34 @@
+package model
+
+import "gorm.io/gorm"
+
+// CommentContent 评论内容表（与 comment_index 1:1 关联，存放正文等大字段）
+type CommentContent struct {
+	CommentID int64          `json:"comment_id" gorm:"primarykey;column:comment_id;autoIncrement:false"` // 评论ID，与 comment_index.id 一致
+	Content   string         `json:"content" gorm:"column:content;type:text;not null"`                   // 评论内容
+	ExtraData MediaExtraJSON `json:"extra_data" gorm:"column:extra_data;type:jsonb;default:'{}'::jsonb"` // 扩展数据（图片、富文本结构等）
+	Deleted   int16          `json:"deleted" gorm:"column:deleted;type:smallint;default:0"`              // 逻辑删除
+}
+
+// TableName 指定表名
+func (CommentContent) TableName() string {
+	return "comment_content"
+}
+
+// GetCommentContents 根据评论ID列表批量获取评论内容，返回以评论ID为键的 map
+// 列表查询只读取索引表，内容统一通过此方法按主键回表
+func GetCommentContents(db *gorm.DB, commentIDs []int64) (map[int64]CommentContent, error) {
+	result := make(map[int64]CommentContent, len(commentIDs))
+	if len(commentIDs) == 0 {
+		return result, nil
+	}
+
+	var contents []CommentContent
+	if err := db.Where("comment_id IN ?", commentIDs).Find(&contents).Error; err != nil {
+		return nil, err
+	}
+	for _, ct := range contents {
+		result[ct.CommentID] = ct
+	}
+	return result, nil
+}
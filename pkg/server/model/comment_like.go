@@ -103,3 +103,59 @@ func ReactivateCommentLike(db *gorm.DB, userID, commentID int64) error {
 		Where("user_id = ? AND comment_id = ?", userID, commentID).
 		Update("deleted", CommentLikeActive).Error
 }
+
+// GetLikedCommentIDs 批量查询用户点赞过的评论，返回以评论ID为键的集合
+func GetLikedCommentIDs(db *gorm.DB, userID int64, commentIDs []int64) (map[int64]bool, error) {
+	liked := make(map[int64]bool)
+	if len(commentIDs) == 0 {
+		return liked, nil
+	}
+
+	var ids []int64
+	err := db.Model(&CommentLike{}).
+		Where("user_id = ? AND comment_id IN ? AND deleted = ?", userID, commentIDs, CommentLikeActive).
+		Pluck("comment_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// LikeComment 点赞评论（幂等），返回点赞状态是否发生变化
+// 首次点赞插入记录，取消后再点赞重新激活记录并刷新点赞时间
+func LikeComment(db *gorm.DB, userID, commentID, postID int64) (bool, error) {
+	now := time.Now()
+	result := db.Exec(`INSERT INTO comment_like (user_id, comment_id, post_id, deleted, create_time, update_time)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, comment_id) DO UPDATE
+		SET deleted = EXCLUDED.deleted, create_time = EXCLUDED.create_time, update_time = EXCLUDED.update_time
+		WHERE comment_like.deleted = ?`,
+		userID, commentID, postID, CommentLikeActive, now, now, CommentLikeCanceled)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UnlikeComment 取消点赞评论（幂等），返回点赞状态是否发生变化
+func UnlikeComment(db *gorm.DB, userID, commentID int64) (bool, error) {
+	result := db.Model(&CommentLike{}).
+		Where("user_id = ? AND comment_id = ? AND deleted = ?", userID, commentID, CommentLikeActive).
+		Update("deleted", CommentLikeCanceled)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClearCommentLikesByPost 清除帖子下所有评论的点赞记录（帖子删除时调用）
+// 依赖冗余的 post_id 字段，无需先查询帖子下的评论ID
+func ClearCommentLikesByPost(db *gorm.DB, postID int64) error {
+	return db.Model(&CommentLike{}).
+		Where("post_id = ? AND deleted = ?", postID, CommentLikeActive).
+		Update("deleted", CommentLikeCanceled).Error
+}
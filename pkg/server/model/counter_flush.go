@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CounterFlush 计数器写回批次表
+// 每批增量与其批次号在同一事务中写入，重复写回同一批次时能识别并跳过，保证增量只计入一次
+type CounterFlush struct {
+	Batch      string    `json:"batch" gorm:"primarykey;column:batch;type:varchar(32)"`           // 批次号
+	CounterKey string    `json:"counter_key" gorm:"column:counter_key;type:varchar(64);not null"` // 计数器键
+	CreateTime time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (CounterFlush) TableName() string {
+	return "counter_flush"
+}
+
+// ClaimCounterFlush 登记写回批次，需要与写回增量使用同一个事务；返回 false 表示该批次已经写回过
+func ClaimCounterFlush(tx *gorm.DB, batch, counterKey string) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&CounterFlush{Batch: batch, CounterKey: counterKey})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteCounterFlushes 清理指定时间之前的写回批次记录
+func DeleteCounterFlushes(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("create_time < ?", before).Delete(&CounterFlush{})
+	return result.RowsAffected, result.Error
+}
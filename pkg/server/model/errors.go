@@ -0,0 +1,12 @@
+package model
+
+import "errors"
+
+// pgUniqueViolation PostgreSQL 唯一约束冲突的错误码
+const pgUniqueViolation = "23505"
+
+// IsUniqueViolation 判断错误是否为唯一约束冲突（如并发插入同一条唯一记录）
+func IsUniqueViolation(err error) bool {
+	var pgErr interface{ SQLState() string }
+	return errors.As(err, &pgErr) && pgErr.SQLState() == pgUniqueViolation
+}
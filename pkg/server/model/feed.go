@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FeedItem 首页信息流条目
+type FeedItem struct {
+	PostID int64 `json:"post_id" gorm:"column:post_id"`
+	Score  int64 `json:"score" gorm:"column:score"` // 排序分值：发布时间(毫秒)，置顶圈子的帖子额外加权
+}
+
+// FeedQuery 首页信息流查询参数
+type FeedQuery struct {
+	CircleIDs    []int64       // 用户加入的圈子
+	TopCircleIDs []int64       // 用户置顶的圈子，其中的帖子排序提前
+	TopBoost     time.Duration // 置顶圈子帖子的加权时长
+	Since        time.Time     // 只聚合该时间之后发布的帖子
+	Cursor       *FeedItem     // 上一页最后一条，为空表示第一页
+	Limit        int
+}
+
+// GetFeedItems 聚合用户加入的圈子中已发布的帖子（按排序分值、帖子ID倒序）
+func GetFeedItems(db *gorm.DB, q FeedQuery) ([]FeedItem, error) {
+	var items []FeedItem
+	if len(q.CircleIDs) == 0 {
+		return items, nil
+	}
+
+	scoreExpr := "(EXTRACT(EPOCH FROM create_time) * 1000)::BIGINT"
+	var scoreArgs []interface{}
+	if len(q.TopCircleIDs) > 0 {
+		scoreExpr = "(" + scoreExpr + " + CASE WHEN circle_id IN ? THEN ? ELSE 0 END)"
+		scoreArgs = []interface{}{q.TopCircleIDs, q.TopBoost.Milliseconds()}
+	}
+
+	query := db.Model(&Post{}).
+		Select("id AS post_id, "+scoreExpr+" AS score", scoreArgs...).
+		Where("circle_id IN ? AND status = ? AND deleted = ? AND create_time > ?",
+			q.CircleIDs, PostStatusPublished, 0, q.Since)
+
+	if q.Cursor != nil {
+		args := append(append([]interface{}{}, scoreArgs...), q.Cursor.Score, q.Cursor.PostID)
+		query = query.Where("("+scoreExpr+", id) < (?, ?)", args...)
+	}
+
+	err := query.Order("score DESC, id DESC").Limit(q.Limit).Scan(&items).Error
+	return items, err
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HotScoreParams 热度计算参数
+type HotScoreParams struct {
+	Since          time.Time     // 统计窗口起点，只统计该时间之后发生的行为
+	PostHalfLife   time.Duration // 帖子热度半衰期
+	CircleHalfLife time.Duration // 圈子活跃度半衰期
+
+	// 帖子热度权重
+	PostView    float64
+	PostLike    float64
+	PostComment float64
+	PostCollect float64
+
+	// 圈子热度权重
+	CircleNewPost      float64
+	CircleActiveMember float64
+	CircleMemberGrowth float64
+}
+
+// HotScore 热度计算结果
+type HotScore struct {
+	ID  int64 `json:"id" gorm:"column:id"`
+	Hot int   `json:"hot" gorm:"column:hot"`
+}
+
+// RefreshPostHotScores 重新计算帖子热度并写回，返回热度发生变化的帖子
+// 热度 = (浏览*权重 + 点赞*权重 + 评论*权重 + 收藏*权重) * 0.5^(发布时长/半衰期)
+// 统计窗口外、已删除或未发布的帖子热度归零
+func RefreshPostHotScores(db *gorm.DB, p HotScoreParams) ([]HotScore, error) {
+	var changed []HotScore
+	err := db.Raw(`WITH scores AS (
+			SELECT id,
+				CASE WHEN status = @published AND deleted = 0 AND create_time > @since THEN
+					LEAST(ROUND((@view * view_count + @like * like_count + @comment * comment_count + @collect * collect_count)
+						* POWER(0.5, EXTRACT(EPOCH FROM (NOW() - create_time)) / @half_life)), 2147483647)::INT
+				ELSE 0 END AS hot
+			FROM post
+			WHERE hot <> 0 OR (status = @published AND deleted = 0 AND create_time > @since)
+		)
+		UPDATE post SET hot = scores.hot FROM scores
+		WHERE post.id = scores.id AND post.hot <> scores.hot
+		RETURNING post.id, post.hot`,
+		map[string]interface{}{
+			"published": PostStatusPublished,
+			"since":     p.Since,
+			"half_life": p.PostHalfLife.Seconds(),
+			"view":      p.PostView,
+			"like":      p.PostLike,
+			"comment":   p.PostComment,
+			"collect":   p.PostCollect,
+		}).Scan(&changed).Error
+	return changed, err
+}
+
+// RefreshCircleHotScores 重新计算圈子热度并写回，返回热度发生变化的圈子
+// 热度 = 新帖*权重 + 活跃成员*权重 + 新成员*权重，每个帖子、成员最近一次发帖/评论、入圈记录按发生时间衰减
+// 非正常状态或已删除的圈子热度归零
+func RefreshCircleHotScores(db *gorm.DB, p HotScoreParams) ([]HotScore, error) {
+	var changed []HotScore
+	err := db.Raw(`WITH post_score AS (
+			SELECT circle_id, SUM(POWER(0.5, EXTRACT(EPOCH FROM (NOW() - create_time)) / @half_life)) AS s
+			FROM post
+			WHERE status = @published AND deleted = 0 AND create_time > @since
+			GROUP BY circle_id
+		), activity AS (
+			SELECT circle_id, user_id, MAX(create_time) AS last_active FROM (
+				SELECT circle_id, user_id, create_time FROM post
+				WHERE status = @published AND deleted = 0 AND create_time > @since
+				UNION ALL
+				SELECT p.circle_id, c.user_id, c.create_time FROM comment_index c JOIN post p ON p.id = c.post_id
+				WHERE c.status = @comment_normal AND c.create_time > @since
+			) a
+			GROUP BY circle_id, user_id
+		), active_score AS (
+			SELECT circle_id, SUM(POWER(0.5, EXTRACT(EPOCH FROM (NOW() - last_active)) / @half_life)) AS s
+			FROM activity
+			GROUP BY circle_id
+		), growth_score AS (
+			SELECT circle_id, SUM(POWER(0.5, EXTRACT(EPOCH FROM (NOW() - create_time)) / @half_life)) AS s
+			FROM circle_member
+			WHERE status IN @member_statuses AND create_time > @since
+			GROUP BY circle_id
+		), scores AS (
+			SELECT c.id,
+				CASE WHEN c.status = @circle_normal AND c.deleted = 0 THEN
+					LEAST(ROUND(@new_post * COALESCE(ps.s, 0) + @active_member * COALESCE(acs.s, 0)
+						+ @member_growth * COALESCE(gs.s, 0)), 2147483647)::INT
+				ELSE 0 END AS hot
+			FROM circle c
+			LEFT JOIN post_score ps ON ps.circle_id = c.id
+			LEFT JOIN active_score acs ON acs.circle_id = c.id
+			LEFT JOIN growth_score gs ON gs.circle_id = c.id
+		)
+		UPDATE circle SET hot = scores.hot FROM scores
+		WHERE circle.id = scores.id AND circle.hot <> scores.hot
+		RETURNING circle.id, circle.hot`,
+		map[string]interface{}{
+			"published":       PostStatusPublished,
+			"comment_normal":  CommentStatusNormal,
+			"circle_normal":   CircleStatusNormal,
+			"member_statuses": []int16{MemberStatusNormal, MemberStatusMuted},
+			"since":           p.Since,
+			"half_life":       p.CircleHalfLife.Seconds(),
+			"new_post":        p.CircleNewPost,
+			"active_member":   p.CircleActiveMember,
+			"member_growth":   p.CircleMemberGrowth,
+		}).Scan(&changed).Error
+	return changed, err
+}
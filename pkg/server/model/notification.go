@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification 站内通知表
+type Notification struct {
+	ID         int64     `json:"id" gorm:"primarykey;column:id"`
+	UserID     int64     `json:"user_id" gorm:"column:user_id;not null"`                // 接收人ID
+	Type       int16     `json:"type" gorm:"column:type;type:smallint;default:1"`       // 通知类型
+	Title      string    `json:"title" gorm:"column:title;type:varchar(100)"`           // 标题
+	Content    string    `json:"content" gorm:"column:content;type:varchar(1000)"`      // 内容
+	CircleID   int64     `json:"circle_id" gorm:"column:circle_id;default:0"`           // 关联圈子ID
+	RelatedID  int64     `json:"related_id" gorm:"column:related_id;default:0"`         // 关联对象ID
+	IsRead     int16     `json:"is_read" gorm:"column:is_read;type:smallint;default:0"` // 是否已读
+	CreateTime time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notification"
+}
+
+// NotificationType 通知类型常量
+const (
+	NotificationTypeSystem  = 1 // 系统通知
+	NotificationTypeCircle  = 2 // 圈子通知(解散/转让等)
+	NotificationTypePost    = 3 // 帖子通知(审核结果等)
+	NotificationTypeComment = 4 // 评论通知(折叠/删除等)
+)
+
+// GetNotificationsByUser 获取用户的通知列表
+func GetNotificationsByUser(db *gorm.DB, userID int64, unreadOnly bool, page, pageSize int) ([]Notification, int64, error) {
+	var notifications []Notification
+	var total int64
+
+	query := db.Model(&Notification{}).Where("user_id = ?", userID)
+
+	if unreadOnly {
+		query = query.Where("is_read = ?", 0)
+	}
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("create_time DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&notifications).Error
+
+	return notifications, total, err
+}
+
+// CountUnreadNotifications 获取用户的未读通知数
+func CountUnreadNotifications(db *gorm.DB, userID int64) (int64, error) {
+	var count int64
+	err := db.Model(&Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, 0).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateNotification 创建通知
+func CreateNotification(db *gorm.DB, notification *Notification) error {
+	return db.Create(notification).Error
+}
+
+// NotifyCircleMembers 向圈子所有成员批量发送通知（单条 INSERT ... SELECT，不逐个插入）
+// 开启了消息免打扰的成员不会收到圈子通知
+func NotifyCircleMembers(db *gorm.DB, circleID int64, notification *Notification) error {
+	return db.Exec(`INSERT INTO notification (user_id, type, title, content, circle_id, related_id, is_read, create_time)
+		SELECT user_id, ?, ?, ?, ?, ?, 0, ? FROM circle_member WHERE circle_id = ? AND status IN ? AND is_disturb = 0`,
+		notification.Type, notification.Title, notification.Content, circleID, notification.RelatedID, time.Now(),
+		circleID, []int16{MemberStatusNormal, MemberStatusMuted},
+	).Error
+}
+
+// MarkNotificationsRead 将通知标记为已读，ids 为空时标记该用户的全部通知
+func MarkNotificationsRead(db *gorm.DB, userID int64, ids []int64) error {
+	query := db.Model(&Notification{}).Where("user_id = ? AND is_read = ?", userID, 0)
+
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	return query.Update("is_read", 1).Error
+}
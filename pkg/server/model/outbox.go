@@ -0,0 +1,125 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxEvent 事件发件箱表
+// 业务写操作和事件在同一事务中写入，事务提交后由中继任务投递到 RabbitMQ，保证写库成功的事件至少投递一次
+type OutboxEvent struct {
+	ID            int64      `json:"id" gorm:"primarykey;column:id"`
+	EventID       string     `json:"event_id" gorm:"column:event_id;type:varchar(32);not null"`             // 事件ID（信封中的ID）
+	EventType     string     `json:"event_type" gorm:"column:event_type;type:varchar(64);not null"`         // 事件类型
+	Version       int        `json:"version" gorm:"column:version;default:1"`                               // 载荷版本
+	AggregateType string     `json:"aggregate_type" gorm:"column:aggregate_type;type:varchar(32);not null"` // 聚合类型(circle/post/comment/user)
+	AggregateID   int64      `json:"aggregate_id" gorm:"column:aggregate_id;not null"`                      // 聚合ID，同一聚合的事件按写入顺序投递
+	Payload       string     `json:"payload" gorm:"column:payload;type:jsonb;not null"`                     // 事件载荷
+	Status        int16      `json:"status" gorm:"column:status;type:smallint;default:0"`                   // 状态
+	Attempts      int        `json:"attempts" gorm:"column:attempts;default:0"`                             // 已投递失败次数
+	LastError     string     `json:"last_error" gorm:"column:last_error;type:varchar(500);default:''"`      // 最近一次投递失败原因
+	OccurredAt    time.Time  `json:"occurred_at" gorm:"column:occurred_at;not null"`                        // 事件发生时间
+	NextRetryTime time.Time  `json:"next_retry_time" gorm:"column:next_retry_time;not null"`                // 下次可投递时间
+	SentTime      *time.Time `json:"sent_time,omitempty" gorm:"column:sent_time"`                           // 投递成功时间
+	CreateTime    time.Time  `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (OutboxEvent) TableName() string {
+	return "outbox_event"
+}
+
+// OutboxStatus 发件箱事件状态常量
+const (
+	OutboxStatusPending = 0 // 待投递
+	OutboxStatusSent    = 1 // 已投递
+	OutboxStatusParked  = 2 // 投递失败次数过多，已停止投递，需人工排查
+)
+
+// outboxLockKey 中继任务的 PostgreSQL 咨询锁键，保证多实例部署时只有一个中继在投递，不打乱同一聚合的顺序
+const outboxLockKey = 20240601
+
+// CreateOutboxEvents 写入发件箱事件，需要与业务写操作使用同一个事务
+func CreateOutboxEvents(db *gorm.DB, events []*OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return db.Create(events).Error
+}
+
+// TryLockOutbox 尝试获取中继任务的会话级咨询锁
+// conn 需要是固定的数据库连接（gorm.DB.Connection），用完后调用 UnlockOutbox 释放；连接断开时锁自动释放
+func TryLockOutbox(conn *gorm.DB) (bool, error) {
+	var locked bool
+	err := conn.Raw("SELECT pg_try_advisory_lock(?)", outboxLockKey).Scan(&locked).Error
+	return locked, err
+}
+
+// UnlockOutbox 释放中继任务的咨询锁
+func UnlockOutbox(conn *gorm.DB) error {
+	return conn.Exec("SELECT pg_advisory_unlock(?)", outboxLockKey).Error
+}
+
+// GetDueOutboxEvents 获取各聚合中最早的待投递事件，且只返回已到重试时间的，按写入顺序排列
+// 每个聚合只取队首事件，队首未投递成功前同一聚合的后续事件不会被取出；
+// 某个聚合的队首处于退避中时不占用名额，不影响其他聚合的投递
+func GetDueOutboxEvents(db *gorm.DB, limit int) ([]OutboxEvent, error) {
+	var events []OutboxEvent
+	err := db.Raw(`
+		SELECT * FROM (
+			SELECT DISTINCT ON (aggregate_type, aggregate_id) *
+			FROM outbox_event
+			WHERE status = ?
+			ORDER BY aggregate_type, aggregate_id, id
+		) head
+		WHERE next_retry_time <= ?
+		ORDER BY id
+		LIMIT ?`, OutboxStatusPending, time.Now(), limit).
+		Scan(&events).Error
+	return events, err
+}
+
+// MarkOutboxEventSent 标记事件已投递
+func MarkOutboxEventSent(db *gorm.DB, id int64) error {
+	return db.Model(&OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":    OutboxStatusSent,
+			"sent_time": time.Now(),
+		}).Error
+}
+
+// MarkOutboxEventFailed 记录一次投递失败，并设置下次可投递时间
+func MarkOutboxEventFailed(db *gorm.DB, id int64, nextRetryTime time.Time, reason string) error {
+	if r := []rune(reason); len(r) > 500 {
+		reason = string(r[:500])
+	}
+	return db.Model(&OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      reason,
+			"next_retry_time": nextRetryTime,
+		}).Error
+}
+
+// ParkOutboxEvent 投递失败次数过多时停止投递该事件，同一聚合的后续事件继续投递
+func ParkOutboxEvent(db *gorm.DB, id int64, reason string) error {
+	if r := []rune(reason); len(r) > 500 {
+		reason = string(r[:500])
+	}
+	return db.Model(&OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     OutboxStatusParked,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error
+}
+
+// DeleteSentOutboxEvents 清理指定时间之前已投递的事件
+func DeleteSentOutboxEvents(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("status = ? AND sent_time < ?", OutboxStatusSent, before).Delete(&OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
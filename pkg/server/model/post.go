@@ -3,9 +3,13 @@ package model
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Post 帖子主表
@@ -22,14 +26,17 @@ type Post struct {
 	CommentCount  int            `json:"comment_count" gorm:"column:comment_count;default:0"`             // 评论数
 	LikeCount     int            `json:"like_count" gorm:"column:like_count;default:0"`                   // 点赞数
 	CollectCount  int            `json:"collect_count" gorm:"column:collect_count;default:0"`             // 收藏数
+	Hot           int            `json:"hot" gorm:"column:hot;default:0"`                                 // 热度值(定时计算)
 	IsPinned      int16          `json:"is_pinned" gorm:"column:is_pinned;type:smallint;default:0"`       // 是否置顶
 	IsEssence     int16          `json:"is_essence" gorm:"column:is_essence;type:smallint;default:0"`     // 是否加精
 	IsLock        int16          `json:"is_lock" gorm:"column:is_lock;type:smallint;default:0"`           // 是否锁定
 	Status        int16          `json:"status" gorm:"column:status;type:smallint;default:1"`             // 状态
+	ReviewRemark  string         `json:"review_remark,omitempty" gorm:"column:review_remark;type:varchar(500);default:''"` // 审核意见
 	Deleted       int16          `json:"deleted" gorm:"column:deleted;type:smallint;default:0"`           // 逻辑删除
 	CreateTime    time.Time      `json:"create_time" gorm:"column:create_time;autoCreateTime"`
 	UpdateTime    time.Time      `json:"update_time" gorm:"column:update_time;autoUpdateTime"`
 	LastReplyTime *time.Time     `json:"last_reply_time,omitempty" gorm:"column:last_reply_time"`         // 最后回复时间
+	PublishAt     *time.Time     `json:"publish_at,omitempty" gorm:"column:publish_at"`                   // 草稿定时发布时间
 }
 
 // TableName 指定表名
@@ -53,6 +60,18 @@ const (
 	PostStatusBlocked    = 4 // 被屏蔽(软删/违规)
 )
 
+// MaxPinnedPosts 每个圈子最多置顶的帖子数
+const MaxPinnedPosts = 5
+
+// ErrPostNotPublished 帖子未处于发布状态
+var ErrPostNotPublished = errors.New("post is not published")
+
+// ErrPostNotReviewing 帖子未处于审核中状态
+var ErrPostNotReviewing = errors.New("post is not under review")
+
+// ErrPostNotDraft 帖子不是草稿
+var ErrPostNotDraft = errors.New("post is not a draft")
+
 // MediaExtraJSON 媒体扩展信息JSON类型
 type MediaExtraJSON map[string]interface{}
 
@@ -87,6 +106,16 @@ func GetPostByID(db *gorm.DB, postID int64) (*Post, error) {
 	return &post, nil
 }
 
+// GetPostsByIDs 根据ID列表批量获取帖子（不保证顺序）
+func GetPostsByIDs(db *gorm.DB, postIDs []int64) ([]Post, error) {
+	var posts []Post
+	if len(postIDs) == 0 {
+		return posts, nil
+	}
+	err := db.Where("id IN ? AND deleted = ?", postIDs, 0).Find(&posts).Error
+	return posts, err
+}
+
 // GetPostsByCircle 获取圈子下的帖子列表
 func GetPostsByCircle(db *gorm.DB, circleID int64, page, pageSize int) ([]Post, int64, error) {
 	var posts []Post
@@ -97,18 +126,20 @@ func GetPostsByCircle(db *gorm.DB, circleID int64, page, pageSize int) ([]Post,
 	// 获取总数
 	query.Count(&total)
 
-	// 分页查询 (置顶帖在前，然后按创建时间倒序)
-	err := db.Where("circle_id = ? AND is_pinned = ? AND status = ? AND deleted = ?", circleID, 1, PostStatusPublished, 0).
-		Order("create_time DESC").
-		Find(&posts).Error
+	// 分页查询 (置顶帖在前，然后按创建时间倒序)，置顶帖只在第一页返回
+	if page == 1 {
+		err := db.Where("circle_id = ? AND is_pinned = ? AND status = ? AND deleted = ?", circleID, 1, PostStatusPublished, 0).
+			Order("create_time DESC").
+			Find(&posts).Error
 
-	if err != nil {
-		return nil, 0, err
+		if err != nil {
+			return nil, 0, err
+		}
 	}
 
 	// 查询非置顶帖子
 	var normalPosts []Post
-	err = db.Where("circle_id = ? AND is_pinned = ? AND status = ? AND deleted = ?", circleID, 0, PostStatusPublished, 0).
+	err := db.Where("circle_id = ? AND is_pinned = ? AND status = ? AND deleted = ?", circleID, 0, PostStatusPublished, 0).
 		Order("create_time DESC").
 		Offset((page - 1) * pageSize).
 		Limit(pageSize).
@@ -133,6 +164,34 @@ func GetPinnedPostsByCircle(db *gorm.DB, circleID int64) ([]Post, error) {
 	return posts, err
 }
 
+// GetEssencePostsByCircle 获取圈子的精华帖子列表
+func GetEssencePostsByCircle(db *gorm.DB, circleID int64, page, pageSize int) ([]Post, int64, error) {
+	var posts []Post
+	var total int64
+
+	query := db.Model(&Post{}).Where("circle_id = ? AND is_essence = ? AND status = ? AND deleted = ?", circleID, 1, PostStatusPublished, 0)
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("create_time DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&posts).Error
+
+	return posts, total, err
+}
+
+// CountPinnedPosts 统计圈子当前的置顶帖子数
+func CountPinnedPosts(db *gorm.DB, circleID int64) (int64, error) {
+	var count int64
+	err := db.Model(&Post{}).
+		Where("circle_id = ? AND is_pinned = ? AND status = ? AND deleted = ?", circleID, 1, PostStatusPublished, 0).
+		Count(&count).Error
+	return count, err
+}
+
 // GetPostsByUser 获取用户的帖子列表
 func GetPostsByUser(db *gorm.DB, userID int64, page, pageSize int) ([]Post, int64, error) {
 	var posts []Post
@@ -152,6 +211,64 @@ func GetPostsByUser(db *gorm.DB, userID int64, page, pageSize int) ([]Post, int6
 	return posts, total, err
 }
 
+// GetDraftsByUser 获取用户的草稿列表（按最后编辑时间倒序）
+func GetDraftsByUser(db *gorm.DB, userID int64, page, pageSize int) ([]Post, int64, error) {
+	var posts []Post
+	var total int64
+
+	query := db.Model(&Post{}).Where("user_id = ? AND status = ? AND deleted = ?", userID, PostStatusDraft, 0)
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("update_time DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&posts).Error
+
+	return posts, total, err
+}
+
+// GetDueDrafts 获取已到定时发布时间的草稿，按 (publish_at, id) 排序
+// hasCursor 为 true 时只返回排在游标 (cursorTime, cursorID) 之后的草稿，用于一轮扫描内分批获取
+func GetDueDrafts(db *gorm.DB, now time.Time, hasCursor bool, cursorTime time.Time, cursorID int64, limit int) ([]Post, error) {
+	var posts []Post
+	query := db.Where("status = ? AND deleted = ? AND publish_at IS NOT NULL AND publish_at <= ?", PostStatusDraft, 0, now)
+	if hasCursor {
+		query = query.Where("(publish_at, id) > (?, ?)", cursorTime, cursorID)
+	}
+	err := query.Order("publish_at ASC, id ASC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// GetVisiblePostsByUser 获取他人可见的用户帖子列表
+// 只返回已发布的帖子，私密圈子中的帖子仅对该圈子成员可见
+func GetVisiblePostsByUser(db *gorm.DB, authorID, viewerID int64, page, pageSize int) ([]Post, int64, error) {
+	var posts []Post
+	var total int64
+
+	query := db.Model(&Post{}).
+		Where("user_id = ? AND status = ? AND deleted = ?", authorID, PostStatusPublished, 0).
+		Where("circle_id NOT IN (?) OR circle_id IN (?)",
+			db.Model(&Circle{}).Select("id").Where("join_type = ?", CircleJoinTypePrivate),
+			db.Model(&CircleMember{}).Select("circle_id").
+				Where("user_id = ? AND status IN ?", viewerID, []int16{MemberStatusNormal, MemberStatusMuted}))
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("create_time DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&posts).Error
+
+	return posts, total, err
+}
+
 // GetPostsByStatus 根据状态获取帖子列表（用于后台审核）
 func GetPostsByStatus(db *gorm.DB, status int16, page, pageSize int) ([]Post, int64, error) {
 	var posts []Post
@@ -171,6 +288,109 @@ func GetPostsByStatus(db *gorm.DB, status int16, page, pageSize int) ([]Post, in
 	return posts, total, err
 }
 
+// GetReviewingPosts 获取待审核的帖子列表
+// circleIDs 为 nil 时查询全部圈子（平台管理员），为空切片时返回空列表
+func GetReviewingPosts(db *gorm.DB, circleIDs []int64, page, pageSize int) ([]Post, int64, error) {
+	if circleIDs == nil {
+		return GetPostsByStatus(db, PostStatusReviewing, page, pageSize)
+	}
+
+	var posts []Post
+	var total int64
+	if len(circleIDs) == 0 {
+		return posts, 0, nil
+	}
+
+	query := db.Model(&Post{}).Where("circle_id IN ? AND status = ? AND deleted = ?", circleIDs, PostStatusReviewing, 0)
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("create_time DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&posts).Error
+
+	return posts, total, err
+}
+
+// ReviewPost 审核帖子并通知作者（使用事务）
+// status 为 PostStatusPublished（通过）或 PostStatusRejected（驳回），remark 为审核意见
+func ReviewPost(db *gorm.DB, postID int64, status int16, remark string, notification *Notification) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 以审核中状态为条件更新，避免重复审核
+		result := tx.Model(&Post{}).
+			Where("id = ? AND status = ? AND deleted = ?", postID, PostStatusReviewing, 0).
+			Updates(map[string]interface{}{
+				"status":        status,
+				"review_remark": remark,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPostNotReviewing
+		}
+
+		// 2. 审核通过后计入圈子帖子数
+		if status == PostStatusPublished {
+			var post Post
+			if err := tx.Select("circle_id").Where("id = ?", postID).First(&post).Error; err != nil {
+				return err
+			}
+			if err := changeCirclePostCount(tx, post.CircleID, 1); err != nil {
+				return err
+			}
+		}
+
+		// 3. 通知作者审核结果
+		return CreateNotification(tx, notification)
+	})
+}
+
+// ApplyLikeCountDeltas 批量写回点赞数增量（key 为帖子ID，value 为增量）
+// 每批使用一条 UPDATE ... FROM (VALUES ...) 语句，点赞数不会被减为负数
+func ApplyLikeCountDeltas(db *gorm.DB, deltas map[int64]int64) error {
+	return applyCountDeltas(db, "post", "id", "like_count", deltas)
+}
+
+// applyCountDeltas 批量写回计数字段增量，keyColumn 为增量 key 对应的列（通常为主键 id）
+func applyCountDeltas(db *gorm.DB, table, keyColumn, column string, deltas map[int64]int64) error {
+	const batchSize = 500
+
+	ids := make([]int64, 0, len(deltas))
+	for id, delta := range deltas {
+		if delta != 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(ids); start += batchSize {
+			end := start + batchSize
+			if end > len(ids) {
+				end = len(ids)
+			}
+
+			values := make([]string, 0, end-start)
+			args := make([]interface{}, 0, (end-start)*2)
+			for _, id := range ids[start:end] {
+				values = append(values, "(?::BIGINT, ?::INT)")
+				args = append(args, id, deltas[id])
+			}
+
+			sql := fmt.Sprintf(`UPDATE %[1]s SET %[2]s = GREATEST(%[1]s.%[2]s + v.delta, 0)
+				FROM (VALUES %[3]s) AS v(id, delta) WHERE %[1]s.%[4]s = v.id`,
+				table, column, strings.Join(values, ","), keyColumn)
+			if err := tx.Exec(sql, args...).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // IncrementViewCount 增加浏览量
 func IncrementViewCount(db *gorm.DB, postID int64) error {
 	return db.Model(&Post{}).Where("id = ?", postID).
@@ -201,20 +421,217 @@ func DecrementLikeCount(db *gorm.DB, postID int64) error {
 		UpdateColumn("like_count", gorm.Expr("like_count - ?", 1)).Error
 }
 
-// CreatePost 创建帖子（包含权限校验）
+// CreatePost 创建帖子（圈子帖子数只统计已发布的帖子，草稿和审核中的帖子在发布时才计入）
 func CreatePost(db *gorm.DB, post *Post) error {
 	return db.Transaction(func(tx *gorm.DB) error {
+		status := post.Status
+
 		// 1. 插入帖子
 		if err := tx.Create(post).Error; err != nil {
 			return err
 		}
 
+		// status 字段带有默认值，零值(草稿)会被 GORM 替换为默认值，需要回写
+		if post.Status != status {
+			if err := tx.Model(post).UpdateColumn("status", status).Error; err != nil {
+				return err
+			}
+			post.Status = status
+		}
+
+		if status != PostStatusPublished {
+			return nil
+		}
+
 		// 2. 更新圈子的帖子计数
-		if err := tx.Model(&Circle{}).Where("id = ?", post.CircleID).
-			UpdateColumn("post_count", gorm.Expr("post_count + ?", 1)).Error; err != nil {
+		return changeCirclePostCount(tx, post.CircleID, 1)
+	})
+}
+
+// UpdateDraft 更新草稿内容，只能更新作者本人的草稿
+func UpdateDraft(db *gorm.DB, postID, userID int64, updates map[string]interface{}) error {
+	result := db.Model(&Post{}).
+		Where("id = ? AND user_id = ? AND status = ? AND deleted = ?", postID, userID, PostStatusDraft, 0).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPostNotDraft
+	}
+	return nil
+}
+
+// PublishDraft 发布草稿（使用事务）
+// status 为 PostStatusPublished（直接发布）或 PostStatusReviewing（进入审核），发布时间以实际发布时刻为准
+func PublishDraft(db *gorm.DB, post *Post, status int16) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 以草稿状态为条件更新，避免定时任务和手动发布重复处理
+		result := tx.Model(&Post{}).
+			Where("id = ? AND status = ? AND deleted = ?", post.ID, PostStatusDraft, 0).
+			Updates(map[string]interface{}{
+				"status":      status,
+				"publish_at":  nil,
+				"create_time": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPostNotDraft
+		}
+
+		if status != PostStatusPublished {
+			return nil
+		}
+
+		// 2. 直接发布时计入圈子帖子数
+		return changeCirclePostCount(tx, post.CircleID, 1)
+	})
+}
+
+// CancelScheduledPublish 取消草稿的定时发布（作者已无法在圈子发帖时由定时任务调用）
+func CancelScheduledPublish(db *gorm.DB, postID int64, notification *Notification) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Post{}).Where("id = ? AND status = ?", postID, PostStatusDraft).
+			Update("publish_at", nil).Error; err != nil {
 			return err
 		}
+		return CreateNotification(tx, notification)
+	})
+}
 
-		return nil
+// DeletePost 作者删除帖子（使用事务）
+func DeletePost(db *gorm.DB, post *Post) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 锁定帖子，以数据库中的最新状态为准，避免与屏蔽、审核等状态变更并发导致帖子计数偏差
+		var current Post
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted = ?", post.ID, 0).First(&current).Error; err != nil {
+			return err
+		}
+
+		// 2. 逻辑删除帖子
+		if err := tx.Model(&Post{}).Where("id = ?", current.ID).Update("deleted", 1).Error; err != nil {
+			return err
+		}
+
+		// 3. 清除该帖子下所有评论的点赞记录，避免出现在用户"赞过的评论"中
+		if err := ClearCommentLikesByPost(tx, current.ID); err != nil {
+			return err
+		}
+
+		if current.Status != PostStatusPublished {
+			return nil
+		}
+
+		// 4. 已发布的帖子需要更新圈子的帖子计数
+		return changeCirclePostCount(tx, current.CircleID, -1)
+	})
+}
+
+// SetPostPinned 置顶/取消置顶帖子
+func SetPostPinned(db *gorm.DB, postID int64, pinned bool) error {
+	return setPostFlag(db, postID, "is_pinned", pinned)
+}
+
+// SetPostEssence 加精/取消加精帖子
+func SetPostEssence(db *gorm.DB, postID int64, essence bool) error {
+	return setPostFlag(db, postID, "is_essence", essence)
+}
+
+// SetPostLock 锁定/解锁帖子，锁定后禁止编辑和评论
+func SetPostLock(db *gorm.DB, postID int64, locked bool) error {
+	return setPostFlag(db, postID, "is_lock", locked)
+}
+
+// BlockPost 屏蔽帖子并通知作者（使用事务）
+func BlockPost(db *gorm.DB, postID int64, notification *Notification) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 只有已发布的帖子可以被屏蔽，同时取消置顶和加精
+		result := tx.Model(&Post{}).
+			Where("id = ? AND status = ? AND deleted = ?", postID, PostStatusPublished, 0).
+			Updates(map[string]interface{}{
+				"status":     PostStatusBlocked,
+				"is_pinned":  0,
+				"is_essence": 0,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPostNotPublished
+		}
+
+		// 2. 屏蔽后不再计入圈子帖子数
+		var post Post
+		if err := tx.Select("circle_id").Where("id = ?", postID).First(&post).Error; err != nil {
+			return err
+		}
+		if err := changeCirclePostCount(tx, post.CircleID, -1); err != nil {
+			return err
+		}
+
+		// 3. 通知作者
+		return CreateNotification(tx, notification)
+	})
+}
+
+// UnblockPost 取消屏蔽，帖子恢复为发布状态（使用事务）
+func UnblockPost(db *gorm.DB, postID int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Post{}).
+			Where("id = ? AND status = ? AND deleted = ?", postID, PostStatusBlocked, 0).
+			Update("status", PostStatusPublished)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// 恢复发布后重新计入圈子帖子数
+		var post Post
+		if err := tx.Select("circle_id").Where("id = ?", postID).First(&post).Error; err != nil {
+			return err
+		}
+		return changeCirclePostCount(tx, post.CircleID, 1)
 	})
 }
+
+// DecidePublishStatus 根据圈子设置和成员身份决定非草稿帖子的状态
+// 圈子开启信任成员免审且成员为信任成员时直接发布，否则进入审核
+func DecidePublishStatus(circle *Circle, member *CircleMember) int16 {
+	if circle.TrustedSkipReview == 1 && IsTrustedMember(member) {
+		return PostStatusPublished
+	}
+	return PostStatusReviewing
+}
+
+// setPostFlag 设置帖子的运营标记（置顶/加精/锁定），只对已发布的帖子生效
+func setPostFlag(db *gorm.DB, postID int64, column string, enabled bool) error {
+	var value int16
+	if enabled {
+		value = 1
+	}
+	result := db.Model(&Post{}).
+		Where("id = ? AND status = ? AND deleted = ?", postID, PostStatusPublished, 0).
+		Update(column, value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPostNotPublished
+	}
+	return nil
+}
+
+// changeCirclePostCount 调整圈子的帖子数
+func changeCirclePostCount(db *gorm.DB, circleID int64, delta int) error {
+	query := db.Model(&Circle{}).Where("id = ?", circleID)
+	if delta < 0 {
+		// 防止计数被减为负数
+		query = query.Where("post_count >= ?", -delta)
+	}
+	return query.UpdateColumn("post_count", gorm.Expr("post_count + ?", delta)).Error
+}
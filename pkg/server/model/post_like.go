@@ -46,23 +46,53 @@ func IsPostLiked(db *gorm.DB, userID, postID int64) (bool, error) {
 	return count > 0, err
 }
 
-// GetLikedPostsByUser 获取用户点赞过的帖子列表
-func GetLikedPostsByUser(db *gorm.DB, userID int64, page, pageSize int) ([]PostLike, int64, error) {
-	var likes []PostLike
+// GetLikedPostIDs 批量查询用户点赞过的帖子，返回以帖子ID为键的集合
+func GetLikedPostIDs(db *gorm.DB, userID int64, postIDs []int64) (map[int64]bool, error) {
+	liked := make(map[int64]bool)
+	if len(postIDs) == 0 {
+		return liked, nil
+	}
+
+	var ids []int64
+	err := db.Model(&PostLike{}).
+		Where("user_id = ? AND post_id IN ? AND deleted = ?", userID, postIDs, PostLikeActive).
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// GetLikedPostsByUser 获取用户点赞过的帖子列表（按点赞时间倒序）
+// 只返回已发布且未删除的帖子，私密圈子的帖子仅在用户仍是正常或禁言成员时返回，总数与列表使用相同的条件
+func GetLikedPostsByUser(db *gorm.DB, userID int64, page, pageSize int) ([]Post, int64, error) {
+	var posts []Post
 	var total int64
 
-	query := db.Model(&PostLike{}).Where("user_id = ? AND deleted = ?", userID, PostLikeActive)
+	query := db.Table("post_like AS pl").
+		Joins("JOIN post ON post.id = pl.post_id").
+		Where("pl.user_id = ? AND pl.deleted = ?", userID, PostLikeActive).
+		Where("post.status = ? AND post.deleted = ?", PostStatusPublished, 0).
+		Where("post.circle_id NOT IN (?) OR post.circle_id IN (?)",
+			db.Model(&Circle{}).Select("id").Where("join_type = ?", CircleJoinTypePrivate),
+			db.Model(&CircleMember{}).Select("circle_id").
+				Where("user_id = ? AND status IN ?", userID, []int16{MemberStatusNormal, MemberStatusMuted}))
 
 	// 获取总数
 	query.Count(&total)
 
 	// 分页查询
-	err := query.Order("create_time DESC").
+	err := query.Select("post.*").
+		Order("pl.create_time DESC, pl.id DESC").
 		Offset((page - 1) * pageSize).
 		Limit(pageSize).
-		Find(&likes).Error
+		Find(&posts).Error
 
-	return likes, total, err
+	return posts, total, err
 }
 
 // GetPostLikers 获取帖子的点赞者列表
@@ -102,3 +132,30 @@ func ReactivatePostLike(db *gorm.DB, userID, postID int64) error {
 		Where("user_id = ? AND post_id = ?", userID, postID).
 		Update("deleted", PostLikeActive).Error
 }
+
+// LikePost 点赞帖子（幂等），返回点赞状态是否发生变化
+// 首次点赞插入记录，取消后再点赞重新激活记录并刷新点赞时间
+func LikePost(db *gorm.DB, userID, postID int64) (bool, error) {
+	now := time.Now()
+	result := db.Exec(`INSERT INTO post_like (user_id, post_id, deleted, create_time, update_time)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, post_id) DO UPDATE
+		SET deleted = EXCLUDED.deleted, create_time = EXCLUDED.create_time, update_time = EXCLUDED.update_time
+		WHERE post_like.deleted = ?`,
+		userID, postID, PostLikeActive, now, now, PostLikeCanceled)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UnlikePost 取消点赞（幂等），返回点赞状态是否发生变化
+func UnlikePost(db *gorm.DB, userID, postID int64) (bool, error) {
+	result := db.Model(&PostLike{}).
+		Where("user_id = ? AND post_id = ? AND deleted = ?", userID, postID, PostLikeActive).
+		Update("deleted", PostLikeCanceled)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,217 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Poll 投票设置表（与投票类型的帖子 1:1 关联）
+type Poll struct {
+	PostID      int64      `json:"post_id" gorm:"primarykey;column:post_id;autoIncrement:false"`    // 所属帖子ID
+	IsMultiple  int16      `json:"is_multiple" gorm:"column:is_multiple;type:smallint;default:0"`   // 是否多选
+	MaxChoices  int        `json:"max_choices" gorm:"column:max_choices;default:1"`                 // 最多可选项数，单选为1
+	IsAnonymous int16      `json:"is_anonymous" gorm:"column:is_anonymous;type:smallint;default:1"` // 是否匿名投票，匿名时不公开投票人
+	HideResults int16      `json:"hide_results" gorm:"column:hide_results;type:smallint;default:0"` // 是否在截止前隐藏结果
+	CloseTime   *time.Time `json:"close_time" gorm:"column:close_time"`                             // 截止时间，为空表示不截止
+	VoterCount  int        `json:"voter_count" gorm:"column:voter_count;default:0"`                 // 参与人数
+	CreateTime  time.Time  `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (Poll) TableName() string {
+	return "post_poll"
+}
+
+// IsClosed 投票是否已截止
+func (p *Poll) IsClosed() bool {
+	return p.CloseTime != nil && !p.CloseTime.After(time.Now())
+}
+
+// PollOption 投票选项表
+type PollOption struct {
+	ID        int64  `json:"id" gorm:"primarykey;column:id"`
+	PostID    int64  `json:"post_id" gorm:"column:post_id;not null"`                   // 所属帖子ID
+	Content   string `json:"content" gorm:"column:content;type:varchar(100);not null"` // 选项内容
+	SortOrder int    `json:"sort_order" gorm:"column:sort_order;default:0"`            // 排序
+	VoteCount int    `json:"vote_count" gorm:"column:vote_count;default:0"`            // 得票数
+}
+
+// TableName 指定表名
+func (PollOption) TableName() string {
+	return "post_poll_option"
+}
+
+// PollVote 投票记录表（每个用户每个投票只有一条记录，多选时记录所有选中的选项）
+type PollVote struct {
+	ID         int64         `json:"id" gorm:"primarykey;column:id"`
+	PostID     int64         `json:"post_id" gorm:"column:post_id;not null"`         // 所属帖子ID
+	UserID     int64         `json:"user_id" gorm:"column:user_id;not null"`         // 投票人
+	OptionIDs  Int64ListJSON `json:"option_ids" gorm:"column:option_ids;type:jsonb"` // 选中的选项ID
+	CreateTime time.Time     `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PollVote) TableName() string {
+	return "post_poll_vote"
+}
+
+// Int64ListJSON 以 JSON 数组存储的 ID 列表
+type Int64ListJSON []int64
+
+// Scan 实现 sql.Scanner 接口
+func (l *Int64ListJSON) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// Value 实现 driver.Valuer 接口
+func (l Int64ListJSON) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// 投票相关限制
+const (
+	MinPollOptions = 2  // 最少选项数
+	MaxPollOptions = 20 // 最多选项数
+)
+
+var (
+	// ErrPollClosed 投票已截止
+	ErrPollClosed = errors.New("poll is closed")
+	// ErrPollAlreadyVoted 用户已经投过票
+	ErrPollAlreadyVoted = errors.New("already voted")
+)
+
+// CreatePostWithPoll 创建投票帖子及其选项（使用事务）
+func CreatePostWithPoll(db *gorm.DB, post *Post, poll *Poll, options []PollOption) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 1. 创建帖子
+		if err := CreatePost(tx, post); err != nil {
+			return err
+		}
+
+		// 2. 创建投票设置，is_multiple / is_anonymous / hide_results 带有默认值，零值需要显式写入
+		poll.PostID = post.ID
+		if err := tx.Create(poll).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(poll).UpdateColumns(map[string]interface{}{
+			"is_multiple":  poll.IsMultiple,
+			"is_anonymous": poll.IsAnonymous,
+			"hide_results": poll.HideResults,
+		}).Error; err != nil {
+			return err
+		}
+
+		// 3. 创建选项
+		for i := range options {
+			options[i].PostID = post.ID
+			options[i].SortOrder = i
+		}
+		return tx.Create(&options).Error
+	})
+}
+
+// GetPollByPostID 获取帖子的投票设置
+func GetPollByPostID(db *gorm.DB, postID int64) (*Poll, error) {
+	var poll Poll
+	err := db.Where("post_id = ?", postID).First(&poll).Error
+	if err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// GetPollOptions 获取投票的选项列表（按排序）
+func GetPollOptions(db *gorm.DB, postID int64) ([]PollOption, error) {
+	var options []PollOption
+	err := db.Where("post_id = ?", postID).Order("sort_order ASC, id ASC").Find(&options).Error
+	return options, err
+}
+
+// GetPollVote 获取用户的投票记录
+func GetPollVote(db *gorm.DB, postID, userID int64) (*PollVote, error) {
+	var vote PollVote
+	err := db.Where("post_id = ? AND user_id = ?", postID, userID).First(&vote).Error
+	if err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+// CastPollVote 投票，依赖 (post_id, user_id) 唯一索引保证每人只能投一次
+// 计数由调用方在同一事务中负责（热点投票的计数先累加到 Redis 再批量写回）
+func CastPollVote(db *gorm.DB, poll *Poll, userID int64, optionIDs []int64) error {
+	if poll.IsClosed() {
+		return ErrPollClosed
+	}
+
+	result := db.Exec(`INSERT INTO post_poll_vote (post_id, user_id, option_ids, create_time)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (post_id, user_id) DO NOTHING`,
+		poll.PostID, userID, Int64ListJSON(optionIDs), time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPollAlreadyVoted
+	}
+	return nil
+}
+
+// GetPollOptionVoters 获取选择了某个选项的投票记录（仅公开投票使用）
+func GetPollOptionVoters(db *gorm.DB, postID, optionID int64, page, pageSize int) ([]PollVote, int64, error) {
+	var votes []PollVote
+	var total int64
+
+	query := db.Model(&PollVote{}).
+		Where("post_id = ? AND option_ids @> ?::jsonb", postID, fmt.Sprintf("[%d]", optionID))
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("create_time DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&votes).Error
+
+	return votes, total, err
+}
+
+// IncrementPollVoteCount 直接在数据库中累加一次投票的选项得票数和参与人数（Redis 不可用时的降级路径）
+// 应与 CastPollVote 在同一事务中调用，保证投票记录与计数一致
+func IncrementPollVoteCount(db *gorm.DB, postID int64, optionIDs []int64) error {
+	err := db.Model(&PollOption{}).Where("id IN ? AND post_id = ?", optionIDs, postID).
+		UpdateColumn("vote_count", gorm.Expr("vote_count + ?", 1)).Error
+	if err != nil {
+		return err
+	}
+	return db.Model(&Poll{}).Where("post_id = ?", postID).
+		UpdateColumn("voter_count", gorm.Expr("voter_count + ?", 1)).Error
+}
+
+// ApplyPollOptionCountDeltas 批量写回选项得票数增量（key 为选项ID）
+func ApplyPollOptionCountDeltas(db *gorm.DB, deltas map[int64]int64) error {
+	return applyCountDeltas(db, "post_poll_option", "id", "vote_count", deltas)
+}
+
+// ApplyPollVoterCountDeltas 批量写回投票参与人数增量（key 为帖子ID）
+func ApplyPollVoterCountDeltas(db *gorm.DB, deltas map[int64]int64) error {
+	return applyCountDeltas(db, "post_poll", "post_id", "voter_count", deltas)
+}
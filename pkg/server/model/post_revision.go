@@ -0,0 +1,146 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostRevision 帖子修订历史表（仅追加，不提供修改和删除）
+type PostRevision struct {
+	ID           int64          `json:"id" gorm:"primarykey;column:id"`
+	PostID       int64          `json:"post_id" gorm:"column:post_id;not null"`                               // 帖子ID
+	Version      int            `json:"version" gorm:"column:version;not null"`                               // 版本号
+	EditorID     int64          `json:"editor_id" gorm:"column:editor_id;not null"`                           // 编辑人ID
+	Title        string         `json:"title" gorm:"column:title;type:varchar(200);default:''"`               // 标题快照
+	Summary      string         `json:"summary" gorm:"column:summary;type:varchar(500);default:''"`           // 摘要快照
+	Content      string         `json:"content" gorm:"column:content;type:text;default:''"`                   // 正文快照
+	MediaExtra   MediaExtraJSON `json:"media_extra" gorm:"column:media_extra;type:jsonb;default:'{}'::jsonb"` // 媒体扩展信息快照
+	Reason       string         `json:"reason" gorm:"column:reason;type:varchar(200);default:''"`             // 编辑理由
+	RestoredFrom int            `json:"restored_from" gorm:"column:restored_from;default:0"`                  // 恢复来源版本号
+	CreateTime   time.Time      `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PostRevision) TableName() string {
+	return "post_revision"
+}
+
+// ErrPostLocked 帖子已锁定，不允许编辑
+var ErrPostLocked = errors.New("post is locked")
+
+// PostEdit 帖子编辑内容
+type PostEdit struct {
+	Title        string
+	Summary      string
+	Content      string
+	MediaExtra   MediaExtraJSON
+	EditorID     int64
+	Reason       string
+	RestoredFrom int // 恢复来源版本号，普通编辑为 0
+}
+
+// GetPostRevisions 获取帖子的修订历史（按版本倒序）
+func GetPostRevisions(db *gorm.DB, postID int64, page, pageSize int) ([]PostRevision, int64, error) {
+	var revisions []PostRevision
+	var total int64
+
+	query := db.Model(&PostRevision{}).Where("post_id = ?", postID)
+
+	// 获取总数
+	query.Count(&total)
+
+	// 分页查询
+	err := query.Order("version DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&revisions).Error
+
+	return revisions, total, err
+}
+
+// GetPostRevision 获取帖子的指定版本
+func GetPostRevision(db *gorm.DB, postID int64, version int) (*PostRevision, error) {
+	var revision PostRevision
+	err := db.Where("post_id = ? AND version = ?", postID, version).First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// EditPost 编辑帖子并写入修订记录（使用事务）
+// 首次编辑时会先把原始内容保存为版本1，保证任意版本都可以追溯和恢复
+func EditPost(db *gorm.DB, postID int64, edit *PostEdit) (*PostRevision, error) {
+	var revision PostRevision
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 1. 锁定帖子行，避免并发编辑产生重复版本号
+		var post Post
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted = ?", postID, 0).
+			First(&post).Error; err != nil {
+			return err
+		}
+		if post.IsLock == 1 {
+			return ErrPostLocked
+		}
+
+		// 2. 查询当前最大版本号
+		var maxVersion int
+		if err := tx.Model(&PostRevision{}).Where("post_id = ?", postID).
+			Select("COALESCE(MAX(version), 0)").Scan(&maxVersion).Error; err != nil {
+			return err
+		}
+
+		// 3. 首次编辑，保存原始内容
+		if maxVersion == 0 {
+			original := PostRevision{
+				PostID:     post.ID,
+				Version:    1,
+				EditorID:   post.UserID,
+				Title:      post.Title,
+				Summary:    post.Summary,
+				Content:    post.Content,
+				MediaExtra: post.MediaExtra,
+				CreateTime: post.CreateTime,
+			}
+			if original.MediaExtra == nil {
+				original.MediaExtra = make(MediaExtraJSON)
+			}
+			if err := tx.Create(&original).Error; err != nil {
+				return err
+			}
+			maxVersion = 1
+		}
+
+		// 4. 写入本次编辑的快照
+		revision = PostRevision{
+			PostID:       post.ID,
+			Version:      maxVersion + 1,
+			EditorID:     edit.EditorID,
+			Title:        edit.Title,
+			Summary:      edit.Summary,
+			Content:      edit.Content,
+			MediaExtra:   edit.MediaExtra,
+			Reason:       edit.Reason,
+			RestoredFrom: edit.RestoredFrom,
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		// 5. 更新帖子内容
+		return tx.Model(&Post{}).Where("id = ?", post.ID).Updates(map[string]interface{}{
+			"title":       edit.Title,
+			"summary":     edit.Summary,
+			"content":     edit.Content,
+			"media_extra": edit.MediaExtra,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
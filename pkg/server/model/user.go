@@ -29,6 +29,21 @@ func (SysUser) TableName() string {
 	return "users"
 }
 
+// UserRole 平台角色常量
+const (
+	UserRoleNormal = 0 // 普通用户
+	UserRoleAdmin  = 1 // 平台管理员
+)
+
+// IsPlatformAdmin 检查用户是否为平台管理员
+func IsPlatformAdmin(db *gorm.DB, userID int64) (bool, error) {
+	var count int64
+	err := db.Model(&SysUser{}).
+		Where("id = ? AND role = ? AND deleted = ?", userID, UserRoleAdmin, 0).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // GetUserByID 根据用户ID从数据库获取用户信息
 func GetUserByID(db *gorm.DB, userID int64) (*SysUser, error) {
 	var user SysUser
@@ -41,3 +56,13 @@ func GetUserByID(db *gorm.DB, userID int64) (*SysUser, error) {
 	}
 	return &user, nil
 }
+
+// GetUsersByIDs 根据用户ID列表批量获取用户信息
+func GetUsersByIDs(db *gorm.DB, userIDs []int64) ([]SysUser, error) {
+	var users []SysUser
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+	err := db.Where("id IN ? AND deleted = ?", userIDs, 0).Find(&users).Error
+	return users, err
+}
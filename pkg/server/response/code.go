@@ -45,6 +45,15 @@ type PaginationResponse struct {
 	PerPage int          `json:"per_page,omitempty"`
 }
 
+// CursorPaginationResponse represents a cursor-based paginated response
+type CursorPaginationResponse struct {
+	Code       ResponseCode `json:"code"`
+	Message    string       `json:"message"`
+	Data       interface{}  `json:"data,omitempty"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	HasMore    bool         `json:"has_more"`
+}
+
 // Predefined error messages
 const (
 	// Success messages
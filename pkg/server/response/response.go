@@ -151,3 +151,14 @@ func PaginationWithMessage(c *gin.Context, message string, data interface{}, tot
 		PerPage: perPage,
 	})
 }
+
+// CursorPagination sends a cursor-based paginated response
+func CursorPagination(c *gin.Context, data interface{}, nextCursor string, hasMore bool) {
+	c.JSON(GetHTTPStatus(CodeSuccess), CursorPaginationResponse{
+		Code:       CodeSuccess,
+		Message:    GetMessage(CodeSuccess),
+		Data:       data,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	})
+}
@@ -9,6 +9,11 @@ import (
 )
 
 func RegisterRoutes(r *gin.RouterGroup) {
+	// 健康检查（公开访问，供负载均衡和容器编排探测）
+	systemCtrl := controller.NewSystemController()
+	r.GET("health", systemCtrl.HealthCheck)
+	r.GET("ready", systemCtrl.ReadinessCheck)
+
 	// Auth routes (公开访问，不需要鉴权)
 	auth := r.Group("auth")
 	{
@@ -44,12 +49,87 @@ func RegisterRoutes(r *gin.RouterGroup) {
 	{
 		// 创建兴趣圈接口 - 需要登录
 		circle.POST("/create", sagin.CheckLogin(), circleCtrl.CreateCircle)
+		// 更新圈子资料 - 仅圈主和管理员
+		circle.POST("/update", sagin.CheckLogin(), circleCtrl.UpdateCircle)
 		// 发帖接口 - 需要登录
 		circle.POST("/post/create", sagin.CheckLogin(), circleCtrl.CreatePost)
+		// 圈子帖子列表 - 私密圈子仅成员可见
+		circle.GET("/post/list", sagin.CheckLogin(), circleCtrl.GetCirclePosts)
+		// 搜索帖子 - 支持按圈子/类型/精华/时间筛选
+		circle.GET("/post/search", sagin.CheckLogin(), circleCtrl.SearchPosts)
+		// 帖子详情
+		circle.GET("/post/detail/:id", sagin.CheckLogin(), circleCtrl.GetPostDetail)
+		// 用户帖子列表 - 查询自己时包含草稿
+		circle.GET("/post/user", sagin.CheckLogin(), circleCtrl.GetUserPosts)
+		// 编辑帖子及修订历史 - 作者本人或圈子管理员
+		circle.POST("/post/edit", sagin.CheckLogin(), circleCtrl.EditPost)
+		circle.GET("/post/revisions", sagin.CheckLogin(), circleCtrl.GetPostRevisions)
+		circle.POST("/post/revision/restore", sagin.CheckLogin(), circleCtrl.RestorePostRevision)
+		// 草稿管理 - 仅作者本人，删除草稿使用 /post/delete
+		circle.GET("/draft/list", sagin.CheckLogin(), circleCtrl.GetDrafts)
+		circle.POST("/draft/update", sagin.CheckLogin(), circleCtrl.UpdateDraft)
+		circle.POST("/draft/publish", sagin.CheckLogin(), circleCtrl.PublishDraft)
+		// 帖子管理 - 置顶/加精/锁定/屏蔽仅圈子管理员，删除仅作者本人
+		circle.POST("/post/pin", sagin.CheckLogin(), circleCtrl.PinPost)
+		circle.POST("/post/essence", sagin.CheckLogin(), circleCtrl.EssencePost)
+		circle.POST("/post/lock", sagin.CheckLogin(), circleCtrl.LockPost)
+		circle.POST("/post/block", sagin.CheckLogin(), circleCtrl.BlockPost)
+		circle.POST("/post/delete", sagin.CheckLogin(), circleCtrl.DeletePost)
+		// 精华帖子列表
+		circle.GET("/post/essence", sagin.CheckLogin(), circleCtrl.GetEssencePosts)
+		// 帖子点赞
+		circle.POST("/post/like", sagin.CheckLogin(), circleCtrl.LikePost)
+		circle.POST("/post/unlike", sagin.CheckLogin(), circleCtrl.UnlikePost)
+		circle.GET("/post/liked", sagin.CheckLogin(), circleCtrl.GetLikedPosts)
+		// 参与投票
+		circle.POST("/poll/vote", sagin.CheckLogin(), circleCtrl.VotePoll)
+		// 获取投票详情和结果
+		circle.GET("/poll/detail", sagin.CheckLogin(), circleCtrl.GetPoll)
+		// 获取选项的投票人列表（公开投票）
+		circle.GET("/poll/voters", sagin.CheckLogin(), circleCtrl.GetPollVoters)
+		// 帖子审核 - 圈主/管理员审核本圈帖子，平台管理员审核全部帖子
+		circle.GET("/review/list", sagin.CheckLogin(), circleCtrl.GetReviewQueue)
+		circle.POST("/review/approve", sagin.CheckLogin(), circleCtrl.ApprovePost)
+		circle.POST("/review/reject", sagin.CheckLogin(), circleCtrl.RejectPost)
 		// 获取圈子列表
 		circle.GET("/list", sagin.CheckLogin(), circleCtrl.GetCircles)
 		// 获取圈子详情
 		circle.GET("/detail/:id", sagin.CheckLogin(), circleCtrl.GetCircleDetail)
+		// 加入/退出圈子
+		circle.POST("/join", sagin.CheckLogin(), circleCtrl.JoinCircle)
+		circle.POST("/leave", sagin.CheckLogin(), circleCtrl.LeaveCircle)
+		// 入圈申请审核 - 需要圈子管理员权限
+		circle.GET("/apply/list", sagin.CheckLogin(), circleCtrl.GetApplications)
+		circle.POST("/apply/approve", sagin.CheckLogin(), circleCtrl.ApproveApplication)
+		circle.POST("/apply/reject", sagin.CheckLogin(), circleCtrl.RejectApplication)
+		// 邀请码/邀请链接 - 创建、查看、撤销需要圈子管理员权限
+		circle.POST("/invite/create", sagin.CheckLogin(), circleCtrl.CreateInvite)
+		circle.GET("/invite/list", sagin.CheckLogin(), circleCtrl.GetInvites)
+		circle.POST("/invite/revoke", sagin.CheckLogin(), circleCtrl.RevokeInvite)
+		circle.GET("/invite/records", sagin.CheckLogin(), circleCtrl.GetInviteRecords)
+		// 使用邀请码加入圈子
+		circle.POST("/invite/redeem", sagin.CheckLogin(), circleCtrl.RedeemInvite)
+		// 成员管理 - 需要圈子管理员权限，设置/取消管理员仅圈主可用
+		circle.GET("/member/list", sagin.CheckLogin(), circleCtrl.GetMembers)
+		circle.POST("/member/promote", sagin.CheckLogin(), circleCtrl.PromoteMember)
+		circle.POST("/member/demote", sagin.CheckLogin(), circleCtrl.DemoteMember)
+		circle.POST("/member/mute", sagin.CheckLogin(), circleCtrl.MuteMember)
+		circle.POST("/member/ban", sagin.CheckLogin(), circleCtrl.BanMember)
+		circle.POST("/member/kick", sagin.CheckLogin(), circleCtrl.KickMember)
+		circle.POST("/member/restore", sagin.CheckLogin(), circleCtrl.RestoreMember)
+		circle.GET("/member/logs", sagin.CheckLogin(), circleCtrl.GetMemberLogs)
+		// 设置信任成员 - 圈主/管理员
+		circle.POST("/member/trust", sagin.CheckLogin(), circleCtrl.TrustMember)
+		// 个人圈子设置（置顶显示、消息免打扰）
+		circle.POST("/member/setting", sagin.CheckLogin(), circleCtrl.UpdateMemberSetting)
+		// 圈主转让 - 发起/取消仅圈主可用，接受/拒绝仅接收人可用
+		circle.POST("/transfer/create", sagin.CheckLogin(), circleCtrl.CreateTransfer)
+		circle.POST("/transfer/cancel", sagin.CheckLogin(), circleCtrl.CancelTransfer)
+		circle.GET("/transfer/incoming", sagin.CheckLogin(), circleCtrl.GetIncomingTransfers)
+		circle.POST("/transfer/accept", sagin.CheckLogin(), circleCtrl.AcceptTransfer)
+		circle.POST("/transfer/decline", sagin.CheckLogin(), circleCtrl.DeclineTransfer)
+		// 解散圈子 - 仅圈主
+		circle.POST("/dissolve", sagin.CheckLogin(), circleCtrl.DissolveCircle)
 	}
 
 	// Category routes
@@ -60,4 +140,88 @@ func RegisterRoutes(r *gin.RouterGroup) {
 		category.GET("/get", sagin.CheckLogin(), categoryCtrl.GetCategories)
 	}
 
+	// Notification routes (需要登录)
+	notificationCtrl := controller.NewNotificationController()
+	notification := r.Group("notification")
+	{
+		// 获取通知列表
+		notification.GET("/list", sagin.CheckLogin(), notificationCtrl.GetNotifications)
+		// 获取未读数
+		notification.GET("/unread", sagin.CheckLogin(), notificationCtrl.GetUnreadCount)
+		// 标记已读
+		notification.POST("/read", sagin.CheckLogin(), notificationCtrl.MarkRead)
+	}
+
+	// Feed routes (需要登录)
+	feedCtrl := controller.NewFeedController()
+	feed := r.Group("feed")
+	{
+		// 首页信息流
+		feed.GET("/home", sagin.CheckLogin(), feedCtrl.GetHomeFeed)
+	}
+
+	// Collect routes (需要登录)
+	collectCtrl := controller.NewCollectController()
+	collect := r.Group("collect")
+	{
+		// 创建收藏夹
+		collect.POST("/folder/create", sagin.CheckLogin(), collectCtrl.CreateFolder)
+		// 更新收藏夹（重命名、公开/私密）
+		collect.POST("/folder/update", sagin.CheckLogin(), collectCtrl.UpdateFolder)
+		// 删除收藏夹
+		collect.POST("/folder/delete", sagin.CheckLogin(), collectCtrl.DeleteFolder)
+		// 获取收藏夹列表
+		collect.GET("/folder/list", sagin.CheckLogin(), collectCtrl.GetFolders)
+		// 获取收藏夹中的帖子
+		collect.GET("/folder/posts", sagin.CheckLogin(), collectCtrl.GetFolderPosts)
+		// 收藏帖子
+		collect.POST("/add", sagin.CheckLogin(), collectCtrl.CollectPost)
+		// 取消收藏
+		collect.POST("/remove", sagin.CheckLogin(), collectCtrl.UncollectPost)
+	}
+
+	// Comment routes (需要登录)
+	commentCtrl := controller.NewCommentController()
+	comment := r.Group("comment")
+	{
+		// 发表评论
+		comment.POST("/create", sagin.CheckLogin(), commentCtrl.CreateComment)
+		// 回复评论
+		comment.POST("/reply", sagin.CheckLogin(), commentCtrl.ReplyComment)
+		// 获取帖子评论列表（附带前几条回复）
+		comment.GET("/list", sagin.CheckLogin(), commentCtrl.GetComments)
+		// 获取根评论下的回复列表
+		comment.GET("/replies", sagin.CheckLogin(), commentCtrl.GetReplies)
+		// 删除自己的评论
+		comment.POST("/delete", sagin.CheckLogin(), commentCtrl.DeleteComment)
+		// 点赞评论
+		comment.POST("/like", sagin.CheckLogin(), commentCtrl.LikeComment)
+		// 取消点赞评论
+		comment.POST("/unlike", sagin.CheckLogin(), commentCtrl.UnlikeComment)
+		// 获取评论的点赞者列表
+		comment.GET("/likers", sagin.CheckLogin(), commentCtrl.GetCommentLikers)
+		// 获取已折叠的评论
+		comment.GET("/folded", sagin.CheckLogin(), commentCtrl.GetFoldedComments)
+		// 折叠评论（圈子管理员）
+		comment.POST("/fold", sagin.CheckLogin(), commentCtrl.FoldComment)
+		// 评论送审（圈子管理员）
+		comment.POST("/review", sagin.CheckLogin(), commentCtrl.ReviewComment)
+		// 恢复评论（圈子管理员）
+		comment.POST("/restore", sagin.CheckLogin(), commentCtrl.RestoreComment)
+		// 删除任意评论（圈子管理员）
+		comment.POST("/remove", sagin.CheckLogin(), commentCtrl.RemoveComment)
+	}
+
+	// Admin routes (需要登录，仅平台管理员)
+	deadLetterCtrl := controller.NewDeadLetterController()
+	admin := r.Group("admin")
+	{
+		// 查看订阅者的死信（平台管理员）
+		admin.GET("/mq/dead/list", sagin.CheckLogin(), deadLetterCtrl.GetDeadLetters)
+		// 重放死信（平台管理员）
+		admin.POST("/mq/dead/replay", sagin.CheckLogin(), deadLetterCtrl.ReplayDeadLetters)
+		// 清除死信（平台管理员）
+		admin.POST("/mq/dead/purge", sagin.CheckLogin(), deadLetterCtrl.PurgeDeadLetters)
+	}
+
 }
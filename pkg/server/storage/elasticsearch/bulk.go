@@ -0,0 +1,102 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// bulkBatchSize 每次 _bulk 请求包含的文档数
+const bulkBatchSize = 500
+
+// BulkUpdateHot 批量更新文档的热度字段（key 为文档ID，value 为热度值）
+// 尚未建立索引的文档（404）会被忽略，其他失败的条目汇总后返回错误
+func BulkUpdateHot(index string, scores map[int64]int) error {
+	ids := make([]int64, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+
+	for start := 0; start < len(ids); start += bulkBatchSize {
+		end := start + bulkBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var body bytes.Buffer
+		for _, id := range ids[start:end] {
+			action := map[string]interface{}{
+				"update": map[string]interface{}{"_index": index, "_id": strconv.FormatInt(id, 10)},
+			}
+			doc := map[string]interface{}{
+				"doc": map[string]interface{}{"hot": scores[id]},
+			}
+			for _, line := range []interface{}{action, doc} {
+				data, err := json.Marshal(line)
+				if err != nil {
+					return fmt.Errorf("failed to marshal bulk line: %w", err)
+				}
+				body.Write(data)
+				body.WriteByte('\n')
+			}
+		}
+
+		if err := doBulk(&body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bulkResponse _bulk 接口响应中需要关注的部分
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// doBulk 执行一次 _bulk 请求并检查每个条目的结果
+func doBulk(body *bytes.Buffer) error {
+	res, err := Client.Bulk(bytes.NewReader(body.Bytes()), Client.Bulk.WithRefresh("false"))
+	if err != nil {
+		return fmt.Errorf("failed to execute bulk request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch bulk error: %s", res.String())
+	}
+
+	var result bulkResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to parse bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+
+	failed := 0
+	var firstErr string
+	for _, item := range result.Items {
+		for _, r := range item {
+			if r.Error == nil || r.Status == 404 {
+				continue
+			}
+			failed++
+			if firstErr == "" {
+				firstErr = fmt.Sprintf("id=%s, %s: %s", r.ID, r.Error.Type, r.Error.Reason)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d bulk items failed, first error: %s", failed, firstErr)
+	}
+	return nil
+}
@@ -67,6 +67,9 @@ func InitElasticsearch() error {
 	if err := createCircleIndex(); err != nil {
 		return fmt.Errorf("failed to create circle index: %w", err)
 	}
+	if err := createPostIndex(); err != nil {
+		return fmt.Errorf("failed to create post index: %w", err)
+	}
 
 	return nil
 }
@@ -233,7 +236,8 @@ func UpdateCircle(circleID int64, name string, avatarURL string, description str
 		JoinType:    joinType,
 	}
 
-	docJSON, err := json.Marshal(doc)
+	// _update 接口需要把字段放在 doc 中，文档不存在时按 doc 新建
+	docJSON, err := json.Marshal(map[string]interface{}{"doc": doc, "doc_as_upsert": true})
 	if err != nil {
 		return fmt.Errorf("failed to marshal document: %w", err)
 	}
@@ -0,0 +1,375 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"interestBar/pkg/conf"
+	"interestBar/pkg/logger"
+	"strconv"
+)
+
+// defaultPostIndex 帖子索引名，配置文件中未设置 post_index 时使用
+const defaultPostIndex = "post"
+
+// 帖子搜索排序方式
+const (
+	PostSortRelevance = "relevance" // 相关度优先（有关键字时的默认值）
+	PostSortNewest    = "newest"    // 最新发布（无关键字时的默认值）
+	PostSortHot       = "hot"       // 热度优先
+)
+
+// PostDocument 帖子文档结构
+type PostDocument struct {
+	ID           int64  `json:"id"`
+	CircleID     int64  `json:"circle_id"`
+	UserID       int64  `json:"user_id"`
+	Type         int16  `json:"type"`
+	Title        string `json:"title"`
+	Summary      string `json:"summary"`
+	Content      string `json:"content,omitempty"`
+	Status       int16  `json:"status"`
+	Deleted      int16  `json:"deleted"`
+	IsEssence    int16  `json:"is_essence"`
+	ViewCount    int    `json:"view_count"`
+	LikeCount    int    `json:"like_count"`
+	CommentCount int    `json:"comment_count"`
+	CollectCount int    `json:"collect_count"`
+	Hot          int    `json:"hot"`
+	CreateTime   string `json:"create_time"` // 使用ISO 8601格式字符串
+	// 命中的高亮片段（字段名 -> 片段列表），仅搜索结果中返回
+	Highlight map[string][]string `json:"highlight,omitempty"`
+}
+
+// PostSearchQuery 帖子搜索条件
+type PostSearchQuery struct {
+	Keyword         string        // 搜索关键字，匹配标题、摘要和正文
+	CircleID        int64         // 按圈子筛选，0 表示不限
+	Type            int16         // 按帖子类型筛选，0 表示不限
+	EssenceOnly     bool          // 只返回精华帖
+	StartTime       string        // 发布时间下限(ISO 8601)，为空表示不限
+	EndTime         string        // 发布时间上限(ISO 8601)，为空表示不限
+	HiddenCircleIDs []int64       // 对当前用户不可见的圈子，其中的帖子不返回
+	Sort            string        // 排序方式
+	Size            int           // 每页数量，默认 20
+	SearchAfter     []interface{} // 上一页返回的 search_after 值
+}
+
+// PostListResponse 帖子搜索响应
+type PostListResponse struct {
+	Posts       []PostDocument `json:"posts"`
+	Total       int64          `json:"total"`
+	Size        int            `json:"size"`
+	SearchAfter []interface{}  `json:"search_after,omitempty"` // 用于获取下一页
+}
+
+// PostIndex 获取帖子索引名
+func PostIndex() string {
+	if conf.Config.Elasticsearch.PostIndex != "" {
+		return conf.Config.Elasticsearch.PostIndex
+	}
+	return defaultPostIndex
+}
+
+// createPostIndex 创建帖子索引（如果不存在）
+func createPostIndex() error {
+	indexName := PostIndex()
+
+	res, err := Client.Indices.Exists([]string{indexName})
+	if err != nil {
+		return fmt.Errorf("failed to check index existence: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 200 {
+		logger.Log.Info(fmt.Sprintf("Index '%s' already exists", indexName))
+		return nil
+	}
+
+	ikText := map[string]interface{}{
+		"type":            "text",
+		"analyzer":        "ik_max_word",
+		"search_analyzer": "ik_smart",
+	}
+	mapping := map[string]interface{}{
+		"settings": map[string]interface{}{
+			"number_of_shards":   1,
+			"number_of_replicas": 0,
+			"refresh_interval":   conf.Config.Elasticsearch.RefreshInterval,
+		},
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"id":            map[string]interface{}{"type": "long"},
+				"circle_id":     map[string]interface{}{"type": "long"},
+				"user_id":       map[string]interface{}{"type": "long"},
+				"type":          map[string]interface{}{"type": "short"},
+				"title":         ikText,
+				"summary":       ikText,
+				"content":       ikText,
+				"status":        map[string]interface{}{"type": "short"},
+				"deleted":       map[string]interface{}{"type": "short"},
+				"is_essence":    map[string]interface{}{"type": "short"},
+				"view_count":    map[string]interface{}{"type": "integer"},
+				"like_count":    map[string]interface{}{"type": "integer"},
+				"comment_count": map[string]interface{}{"type": "integer"},
+				"collect_count": map[string]interface{}{"type": "integer"},
+				"hot":           map[string]interface{}{"type": "integer"},
+				"create_time":   map[string]interface{}{"type": "date"},
+			},
+		},
+	}
+
+	mappingJSON, err := json.Marshal(mapping)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mapping: %w", err)
+	}
+
+	res, err = Client.Indices.Create(
+		indexName,
+		Client.Indices.Create.WithBody(bytes.NewReader(mappingJSON)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("failed to create index: %s", res.String())
+	}
+
+	logger.Log.Info(fmt.Sprintf("Index '%s' created successfully", indexName))
+	return nil
+}
+
+// IndexPost 同步帖子文档到 ES（不存在则创建，存在则整体覆盖）
+func IndexPost(doc PostDocument) error {
+	docJSON, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	res, err := Client.Index(
+		PostIndex(),
+		bytes.NewReader(docJSON),
+		Client.Index.WithDocumentID(strconv.FormatInt(doc.ID, 10)),
+		Client.Index.WithRefresh("false"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to index document: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error when indexing document: %s", res.String())
+	}
+
+	logger.Log.Info(fmt.Sprintf("Post %d indexed successfully", doc.ID))
+	return nil
+}
+
+// DeletePost 删除帖子文档
+func DeletePost(postID int64) error {
+	res, err := Client.Delete(
+		PostIndex(),
+		strconv.FormatInt(postID, 10),
+		Client.Delete.WithRefresh("false"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != 404 {
+		return fmt.Errorf("elasticsearch error when deleting document: %s", res.String())
+	}
+
+	logger.Log.Info(fmt.Sprintf("Post %d deleted successfully", postID))
+	return nil
+}
+
+// DeletePostsByCircle 删除圈子下的全部帖子文档（圈子解散时使用）
+func DeletePostsByCircle(circleID int64) error {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"circle_id": circleID},
+		},
+	}
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	res, err := Client.DeleteByQuery(
+		[]string{PostIndex()},
+		bytes.NewReader(queryJSON),
+		Client.DeleteByQuery.WithConflicts("proceed"),
+		Client.DeleteByQuery.WithRefresh(false),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete documents by query: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != 404 {
+		return fmt.Errorf("elasticsearch error when deleting documents by query: %s", res.String())
+	}
+
+	logger.Log.Info(fmt.Sprintf("Posts of circle %d deleted successfully", circleID))
+	return nil
+}
+
+// SearchPosts 搜索帖子
+// 只返回已发布且未删除的帖子；有关键字时标题权重最高，其次摘要、正文，并返回高亮片段
+// 分页方式与 SearchCircles 一致：使用上一页返回的 search_after 获取下一页
+func SearchPosts(q PostSearchQuery) (*PostListResponse, error) {
+	size := q.Size
+	if size <= 0 || size > 100 {
+		size = 20
+	}
+
+	// 过滤条件（不参与评分）
+	filters := []map[string]interface{}{
+		{"term": map[string]interface{}{"status": 1}},
+		{"term": map[string]interface{}{"deleted": 0}},
+	}
+	if q.CircleID > 0 {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"circle_id": q.CircleID}})
+	}
+	if q.Type > 0 {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"type": q.Type}})
+	}
+	if q.EssenceOnly {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"is_essence": 1}})
+	}
+	if q.StartTime != "" || q.EndTime != "" {
+		timeRange := map[string]interface{}{}
+		if q.StartTime != "" {
+			timeRange["gte"] = q.StartTime
+		}
+		if q.EndTime != "" {
+			timeRange["lte"] = q.EndTime
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"create_time": timeRange}})
+	}
+
+	boolQuery := map[string]interface{}{
+		"filter": filters,
+	}
+	if len(q.HiddenCircleIDs) > 0 {
+		boolQuery["must_not"] = []map[string]interface{}{
+			{"terms": map[string]interface{}{"circle_id": q.HiddenCircleIDs}},
+		}
+	}
+	if q.Keyword != "" {
+		boolQuery["must"] = []map[string]interface{}{
+			{
+				"multi_match": map[string]interface{}{
+					"query":    q.Keyword,
+					"fields":   []string{"title^3", "summary^2", "content^1"},
+					"type":     "best_fields",
+					"operator": "or",
+				},
+			},
+		}
+	}
+
+	// 排序规则，最后以 id 兜底保证 search_after 翻页稳定
+	sortBy := q.Sort
+	if sortBy == "" && q.Keyword != "" {
+		sortBy = PostSortRelevance
+	}
+	if sortBy == "" || (sortBy == PostSortRelevance && q.Keyword == "") {
+		sortBy = PostSortNewest
+	}
+	var sortRules []map[string]interface{}
+	switch sortBy {
+	case PostSortRelevance:
+		sortRules = append(sortRules, map[string]interface{}{"_score": map[string]interface{}{"order": "desc"}})
+	case PostSortHot:
+		sortRules = append(sortRules, map[string]interface{}{"hot": map[string]interface{}{"order": "desc"}})
+	}
+	sortRules = append(sortRules,
+		map[string]interface{}{"create_time": map[string]interface{}{"order": "desc"}},
+		map[string]interface{}{"id": map[string]interface{}{"order": "desc"}},
+	)
+
+	searchQuery := map[string]interface{}{
+		"query":   map[string]interface{}{"bool": boolQuery},
+		"size":    size,
+		"sort":    sortRules,
+		"_source": map[string]interface{}{"excludes": []string{"content"}}, // 列表不返回正文
+	}
+	if q.Keyword != "" {
+		searchQuery["highlight"] = map[string]interface{}{
+			"pre_tags":  []string{"<em>"},
+			"post_tags": []string{"</em>"},
+			"fields": map[string]interface{}{
+				"title":   map[string]interface{}{"number_of_fragments": 0},
+				"summary": map[string]interface{}{"fragment_size": 100, "number_of_fragments": 1},
+				"content": map[string]interface{}{"fragment_size": 100, "number_of_fragments": 3},
+			},
+		}
+	}
+	if len(q.SearchAfter) > 0 {
+		searchQuery["search_after"] = q.SearchAfter
+	}
+
+	queryJSON, err := json.Marshal(searchQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	res, err := Client.Search(
+		Client.Search.WithIndex(PostIndex()),
+		Client.Search.WithBody(bytes.NewReader(queryJSON)),
+		Client.Search.WithTrackTotalHits(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("elasticsearch search error: %s", res.String())
+	}
+
+	var searchResult struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source    PostDocument        `json:"_source"`
+				Highlight map[string][]string `json:"highlight"`
+				Sort      []interface{}       `json:"sort"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&searchResult); err != nil {
+		return nil, fmt.Errorf("failed to parse search response: %w", err)
+	}
+
+	documents := make([]PostDocument, 0, len(searchResult.Hits.Hits))
+	var nextSearchAfter []interface{}
+	for _, hit := range searchResult.Hits.Hits {
+		doc := hit.Source
+		doc.Highlight = hit.Highlight
+		documents = append(documents, doc)
+		// 记录最后一个文档的排序值
+		if len(hit.Sort) > 0 {
+			nextSearchAfter = hit.Sort
+		}
+	}
+
+	// 如果有更多结果，返回 search_after 用于下一页
+	response := &PostListResponse{
+		Posts: documents,
+		Total: searchResult.Hits.Total.Value,
+		Size:  size,
+	}
+	if len(nextSearchAfter) > 0 && len(documents) == size {
+		response.SearchAfter = nextSearchAfter
+	}
+
+	return response, nil
+}
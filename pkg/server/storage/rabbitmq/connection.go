@@ -0,0 +1,343 @@
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+	"interestBar/pkg/conf"
+	"interestBar/pkg/logger"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// 重连相关的默认配置，配置文件中未设置(为0)时使用
+const (
+	defaultReconnectInitialInterval = 1  // 首次重连间隔(秒)
+	defaultReconnectMaxInterval     = 30 // 最大重连间隔(秒)
+	defaultPublishWaitTimeout       = 10 // 断线时发布消息等待重连的最长时间(秒)
+)
+
+// ErrNotConnected RabbitMQ 未连接（断线期间等待重连超时）
+var ErrNotConnected = errors.New("RabbitMQ is not connected")
+
+// Health RabbitMQ 连接健康状态，用于就绪检查
+type Health struct {
+	Connected  bool            `json:"connected"`
+	Consuming  bool            `json:"consuming"`            // 所有订阅者是否都在消费
+	Consumers  map[string]bool `json:"consumers"`            // 各订阅者的消费者是否在运行
+	Since      time.Time       `json:"since"`                // 当前连接状态的开始时间
+	Reconnects int             `json:"reconnects"`           // 启动以来的重连次数
+	LastError  string          `json:"last_error,omitempty"` // 最近一次断线或连接失败的原因
+}
+
+// 连接状态，均由 connMu 保护
+// 连接可用时 conn、channel 非空且 ready 已关闭；断线后二者置空，ready 换成新的未关闭通道，发布者在其上等待重连
+var (
+	connMu     sync.RWMutex
+	conn       *amqp.Connection
+	channel    *amqp.Channel
+	connClosed chan *amqp.Error // 连接断开通知
+	chanClosed chan *amqp.Error // 发布通道关闭通知（如通道级错误）
+	ready      = make(chan struct{})
+	closing    bool // 已调用 CloseRabbitMQ，不再重连
+	consuming  bool // 已调用 StartConsumer
+	connected  bool // 曾经连接成功过，之后的连接都计为重连
+	health     = Health{Since: time.Now()}
+	consumerUp = make(map[string]bool) // 各订阅者的消费者是否在运行
+)
+
+// InitRabbitMQ 连接 RabbitMQ 并启动连接管理
+// 首次连接失败时返回错误，但连接管理仍会在后台按退避间隔重连；
+// 每次连上后重新声明交换机，各订阅者的消费者在自己的通道上重新声明队列并恢复消费
+func InitRabbitMQ() error {
+	err := connect()
+	go manage()
+	if err != nil {
+		return err
+	}
+
+	logger.Log.Info("RabbitMQ initialized successfully")
+	return nil
+}
+
+// manage 监听连接断开并自动重连，直到调用 CloseRabbitMQ
+func manage() {
+	attempt := 0
+	for {
+		connMu.RLock()
+		stop, c, connDone, chanDone := closing, conn, connClosed, chanClosed
+		connMu.RUnlock()
+		if stop {
+			return
+		}
+
+		if c != nil {
+			attempt = 0
+			var reason *amqp.Error
+			select {
+			case reason = <-connDone:
+			case reason = <-chanDone:
+			}
+			disconnect(c, reason)
+			continue
+		}
+
+		attempt++
+		delay := reconnectDelay(attempt)
+		logger.Log.Info(fmt.Sprintf("Reconnecting to RabbitMQ in %s (attempt %d)", delay, attempt))
+		time.Sleep(delay)
+		if err := connect(); err != nil {
+			logger.Log.Error("Failed to reconnect to RabbitMQ: " + err.Error())
+		}
+	}
+}
+
+// connect 建立连接和发布用的通道，并声明事件交换机
+func connect() error {
+	c, err := amqp.Dial(url())
+	if err != nil {
+		return connectFailed(fmt.Errorf("failed to connect to RabbitMQ: %w", err))
+	}
+
+	ch, err := c.Channel()
+	if err != nil {
+		c.Close()
+		return connectFailed(fmt.Errorf("failed to open RabbitMQ channel: %w", err))
+	}
+
+	// 开启发布确认，Publish 等待 Broker 确认后才返回，保证发件箱中的事件确实已投递
+	if err := ch.Confirm(false); err != nil {
+		c.Close()
+		return connectFailed(fmt.Errorf("failed to enable publisher confirms: %w", err))
+	}
+
+	// 声明 topic 交换机，所有领域事件都发布到这里，按路由键分发给各订阅者的队列
+	err = ch.ExchangeDeclare(
+		exchangeName(),
+		"topic", // 交换机类型
+		true,    // durable
+		false,   // auto-deleted
+		false,   // internal
+		false,   // no-wait
+		nil,     // arguments
+	)
+	if err != nil {
+		c.Close()
+		return connectFailed(fmt.Errorf("failed to declare exchange: %w", err))
+	}
+
+	connMu.Lock()
+	defer connMu.Unlock()
+
+	if closing {
+		c.Close()
+		return fmt.Errorf("RabbitMQ is closing")
+	}
+
+	conn, channel = c, ch
+	connClosed = c.NotifyClose(make(chan *amqp.Error, 1))
+	chanClosed = ch.NotifyClose(make(chan *amqp.Error, 1))
+	close(ready)
+
+	if connected {
+		health.Reconnects++
+		logger.Log.Info("RabbitMQ reconnected")
+	}
+	connected = true
+	health.Connected = true
+	health.Since = time.Now()
+	return nil
+}
+
+// connectFailed 记录连接失败的原因
+func connectFailed(err error) error {
+	connMu.Lock()
+	health.LastError = err.Error()
+	connMu.Unlock()
+	return err
+}
+
+// disconnect 连接 c 或其发布通道断开后清理状态，之后的发布会等待重连
+// 连接管理和发现通道已失效的发布者都可能调用，只处理仍是当前连接的 c
+func disconnect(c *amqp.Connection, reason *amqp.Error) {
+	connMu.Lock()
+	defer connMu.Unlock()
+
+	if closing || conn == nil || conn != c {
+		return
+	}
+
+	// 只有通道关闭时连接仍然存活，一并关闭后统一重连
+	if !conn.IsClosed() {
+		conn.Close()
+	}
+	conn, channel = nil, nil
+	ready = make(chan struct{})
+
+	msg := "connection closed"
+	if reason != nil {
+		msg = reason.Error()
+	}
+	health.Connected = false
+	health.Since = time.Now()
+	health.LastError = msg
+	logger.Log.Warn("RabbitMQ connection lost: " + msg)
+}
+
+// reconnectDelay 计算第 attempt 次重连前的等待时间（指数退避）
+func reconnectDelay(attempt int) time.Duration {
+	cfg := conf.Config.RabbitMQ.Reconnect
+	initial := cfg.InitialInterval
+	if initial <= 0 {
+		initial = defaultReconnectInitialInterval
+	}
+	maxInterval := cfg.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultReconnectMaxInterval
+	}
+
+	delay := time.Duration(initial) * time.Second
+	for i := 1; i < attempt && delay < time.Duration(maxInterval)*time.Second; i++ {
+		delay *= 2
+	}
+	if delay > time.Duration(maxInterval)*time.Second {
+		delay = time.Duration(maxInterval) * time.Second
+	}
+	return delay
+}
+
+// waitForChannel 获取可用的发布通道，断线期间最多等待 publishWaitTimeout 直到重连成功
+// 连接刚断开、连接管理尚未处理时，当前通道已关闭，同样视为断线等待重连
+func waitForChannel() (*amqp.Channel, error) {
+	timeout := conf.Config.RabbitMQ.Reconnect.PublishWaitTimeout
+	if timeout <= 0 {
+		timeout = defaultPublishWaitTimeout
+	}
+	timer := time.NewTimer(time.Duration(timeout) * time.Second)
+	defer timer.Stop()
+
+	for {
+		connMu.RLock()
+		c, ch, wait, stop := conn, channel, ready, closing
+		connMu.RUnlock()
+		if stop {
+			return nil, fmt.Errorf("RabbitMQ is closed")
+		}
+		if ch != nil {
+			if !ch.IsClosed() && !c.IsClosed() {
+				return ch, nil
+			}
+			disconnect(c, nil)
+			continue
+		}
+
+		select {
+		case <-wait:
+		case <-timer.C:
+			return nil, ErrNotConnected
+		}
+	}
+}
+
+// awaitConn 等待可用的连接，调用 CloseRabbitMQ 后返回 false
+func awaitConn() (*amqp.Connection, bool) {
+	for {
+		connMu.RLock()
+		c, wait, stop := conn, ready, closing
+		connMu.RUnlock()
+		if stop {
+			return nil, false
+		}
+		if c == nil {
+			<-wait
+			continue
+		}
+		if c.IsClosed() {
+			disconnect(c, nil)
+			continue
+		}
+		return c, true
+	}
+}
+
+// setConsumerState 记录订阅者的消费者是否在运行
+func setConsumerState(name string, up bool) {
+	connMu.Lock()
+	defer connMu.Unlock()
+	consumerUp[name] = up
+}
+
+// currentConn 获取当前连接，未连接时返回 ErrNotConnected
+func currentConn() (*amqp.Connection, error) {
+	connMu.RLock()
+	defer connMu.RUnlock()
+	if conn == nil {
+		return nil, ErrNotConnected
+	}
+	return conn, nil
+}
+
+// IsReady RabbitMQ 当前是否可用
+func IsReady() bool {
+	connMu.RLock()
+	defer connMu.RUnlock()
+	return channel != nil
+}
+
+// HealthStatus 获取 RabbitMQ 连接健康状态
+func HealthStatus() Health {
+	connMu.RLock()
+	defer connMu.RUnlock()
+	h := health
+	h.Consumers = make(map[string]bool, len(consumerUp))
+	h.Consuming = consuming && h.Connected
+	for name, up := range consumerUp {
+		h.Consumers[name] = up && h.Connected
+		if !up {
+			h.Consuming = false
+		}
+	}
+	return h
+}
+
+// url 获取 RabbitMQ 连接地址
+func url() string {
+	return fmt.Sprintf("amqp://%s:%s@%s:%d%s",
+		conf.Config.RabbitMQ.Username,
+		conf.Config.RabbitMQ.Password,
+		conf.Config.RabbitMQ.Host,
+		conf.Config.RabbitMQ.Port,
+		conf.Config.RabbitMQ.VHost,
+	)
+}
+
+// CloseRabbitMQ 关闭 RabbitMQ 连接并停止重连
+func CloseRabbitMQ() error {
+	connMu.Lock()
+	defer connMu.Unlock()
+
+	if closing {
+		return nil
+	}
+	closing = true
+	if conn == nil {
+		// 唤醒正在等待重连的发布者
+		close(ready)
+		return nil
+	}
+
+	var err error
+	if e := channel.Close(); e != nil {
+		logger.Log.Error("Failed to close RabbitMQ channel: " + e.Error())
+		err = e
+	}
+	if e := conn.Close(); e != nil {
+		logger.Log.Error("Failed to close RabbitMQ connection: " + e.Error())
+		if err == nil {
+			err = e
+		}
+	}
+	conn, channel = nil, nil
+	return err
+}
@@ -3,19 +3,16 @@ package rabbitmq
 // RabbitMQ 相关常量定义
 
 const (
-	// CircleSyncExchange 圈子同步交换机
-	CircleSyncExchange = "circle_sync_exchange"
+	// DefaultExchange 默认事件交换机（topic 类型），配置文件中未设置 exchange 时使用
+	DefaultExchange = "interestbar.events"
 
-	// CircleSyncQueue 圈子同步队列
-	CircleSyncQueue = "circle_sync_queue"
+	// DefaultQueuePrefix 默认队列名前缀，每个订阅者的主队列名为 <前缀>.<订阅者名>.<队列版本>
+	DefaultQueuePrefix = "interestbar"
 
-	// CircleSyncRoutingKey 圈子同步路由键
-	CircleSyncRoutingKey = "circle.sync"
-)
+	// QueueVersion 订阅者主队列的版本，主队列参数变化（已存在的队列无法按新参数重新声明）时递增
+	// v2：主队列增加死信交换机参数，旧版本不带版本号的主队列由消费者启动时迁移并删除
+	QueueVersion = "v2"
 
-// CircleSyncAction 圈子同步操作类型
-const (
-	CircleSyncActionCreate = "create"
-	CircleSyncActionUpdate = "update"
-	CircleSyncActionDelete = "delete"
+	// DefaultRoutingKeyPrefix 默认路由键前缀，事件的路由键为 <前缀>.<事件类型>
+	DefaultRoutingKeyPrefix = "interestbar"
 )
@@ -4,33 +4,151 @@ import (
 	"encoding/json"
 	"fmt"
 	"interestBar/pkg/logger"
-	es "interestBar/pkg/server/storage/elasticsearch"
+	"strings"
+	"sync"
 	"time"
 
-	conf "interestBar/pkg/conf"
-
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-// StartConsumer 启动消费者处理圈子同步消息
-func StartConsumer() error {
-	if channel == nil {
-		return fmt.Errorf("RabbitMQ channel is not initialized")
+// Handler 事件处理函数
+// 返回错误时消息按指数退避重试，超过最大处理次数后进入死信队列；返回 Permanent 包装的错误时直接进入死信队列
+type Handler func(event *Event) error
+
+// route 事件类型模式与处理函数的对应关系
+type route struct {
+	pattern string
+	handler Handler
+}
+
+// subscriber 订阅者，每个订阅者独占一个队列，互不影响
+type subscriber struct {
+	name   string
+	routes []route
+}
+
+var (
+	registryMu  sync.Mutex
+	subscribers []*subscriber
+)
+
+// Subscribe 注册事件处理函数
+// name 为订阅者名（如 search、notification），同名订阅者共享一个队列；
+// pattern 为事件类型模式，支持 topic 通配符：* 匹配一个单词，# 匹配零个或多个单词，如 "post.*"
+// 需要在 StartConsumer 之前调用
+func Subscribe(name, pattern string, handler Handler) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, s := range subscribers {
+		if s.name == name {
+			s.routes = append(s.routes, route{pattern: pattern, handler: handler})
+			return
+		}
+	}
+	subscribers = append(subscribers, &subscriber{
+		name:   name,
+		routes: []route{{pattern: pattern, handler: handler}},
+	})
+}
+
+// StartConsumer 为每个订阅者启动消费者：声明队列、按事件类型模式绑定到事件交换机并开始消费
+// 每个订阅者使用独立的通道，通道或连接断开后自动重新启动；当前未连接时在连接建立后开始消费
+func StartConsumer() {
+	connMu.Lock()
+	if consuming {
+		connMu.Unlock()
+		return
+	}
+	consuming = true
+	connMu.Unlock()
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, s := range subscribers {
+		setConsumerState(s.name, false)
+		go s.run()
+	}
+	logger.Log.Info(fmt.Sprintf("Event consumers starting: subscribers=%d", len(subscribers)))
+}
+
+// run 运行订阅者的消费者，直到调用 CloseRabbitMQ
+// 队列声明或绑定失败（如队列参数冲突）只影响该订阅者，按退避间隔重试，不影响连接和事件发布
+func (s *subscriber) run() {
+	attempt := 0
+	for {
+		c, ok := awaitConn()
+		if !ok {
+			return
+		}
+
+		done, err := s.start(c)
+		if err != nil {
+			attempt++
+			delay := reconnectDelay(attempt)
+			logger.Log.Error(fmt.Sprintf("Failed to start consumer (subscriber=%s), retry in %s: %s", s.name, delay, err.Error()))
+			time.Sleep(delay)
+			continue
+		}
+
+		attempt = 0
+		setConsumerState(s.name, true)
+		logger.Log.Info(fmt.Sprintf("Consumer started: subscriber=%s", s.name))
+
+		reason := <-done
+		setConsumerState(s.name, false)
+		msg := "channel closed"
+		if reason != nil {
+			msg = reason.Error()
+		}
+		logger.Log.Warn(fmt.Sprintf("Consumer stopped (subscriber=%s): %s", s.name, msg))
 	}
+}
 
-	// 设置 QoS，每次只接收一条消息
-	err := channel.Qos(
+// start 在连接上为订阅者打开独立的通道并开始消费，返回通道关闭通知
+func (s *subscriber) start(c *amqp.Connection) (chan *amqp.Error, error) {
+	ch, err := c.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+
+	// 设置 QoS，每个消费者每次只接收一条消息
+	err = ch.Qos(
 		1,     // prefetch count
 		0,     // prefetch size
 		false, // global
 	)
 	if err != nil {
-		return fmt.Errorf("failed to set QoS: %w", err)
+		ch.Close()
+		return nil, fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	done := ch.NotifyClose(make(chan *amqp.Error, 1))
+	if err := consume(ch, s); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	// 当前主队列声明并绑定后，再把旧版本队列中的消息转过来
+	migrateLegacyQueues(c, s)
+	return done, nil
+}
+
+// consume 声明订阅者的队列并注册消费者，逐条分发并手动确认
+func consume(ch *amqp.Channel, s *subscriber) error {
+	if err := declareSubscriberQueues(ch, s.name); err != nil {
+		return err
+	}
+
+	for _, r := range s.routes {
+		if err := ch.QueueBind(queueName(s.name), routingKey(r.pattern), exchangeName(), false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue: %w", err)
+		}
 	}
 
-	// 开始消费消息
-	msgs, err := channel.Consume(
-		CircleSyncQueue,
+	msgs, err := ch.Consume(
+		queueName(s.name),
 		"",    // consumer tag
 		false, // auto-ack (手动确认)
 		false, // exclusive
@@ -42,15 +160,11 @@ func StartConsumer() error {
 		return fmt.Errorf("failed to register consumer: %w", err)
 	}
 
-	logger.Log.Info("Circle sync consumer started")
-
-	// 启动 goroutine 处理消息
+	// 启动 goroutine 处理消息，连接断开时 msgs 关闭，goroutine 随之退出，重连后重新启动
 	go func() {
 		for d := range msgs {
-			if err := processMessage(d); err != nil {
-				logger.Log.Error("Failed to process message: " + err.Error())
-				// 消息处理失败，重新入队（支持重试）
-				d.Nack(false, true)
+			if err := s.dispatch(d); err != nil {
+				s.handleFailure(d, err)
 			} else {
 				d.Ack(false)
 			}
@@ -60,60 +174,54 @@ func StartConsumer() error {
 	return nil
 }
 
-// processMessage 处理单条消息
-func processMessage(d amqp.Delivery) error {
-	var message CircleSyncMessage
-	if err := json.Unmarshal(d.Body, &message); err != nil {
-		return fmt.Errorf("failed to unmarshal message: %w", err)
+// dispatch 解析事件信封，交给所有匹配事件类型的处理函数
+func (s *subscriber) dispatch(d amqp.Delivery) error {
+	var event Event
+	if err := json.Unmarshal(d.Body, &event); err != nil {
+		return Permanent(fmt.Errorf("failed to unmarshal event: %w", err))
 	}
 
-	logger.Log.Info(fmt.Sprintf("Processing circle sync message: action=%s, circle_id=%d", message.Action, message.CircleID))
+	logger.Log.Info(fmt.Sprintf("Processing event: subscriber=%s, type=%s, id=%s", s.name, event.Type, event.ID))
 
-	// 根据操作类型执行相应的 ES 操作
-	switch message.Action {
-	case CircleSyncActionCreate:
-		if err := es.IndexCircle(message.CircleID, message.Name, message.AvatarURL, message.Description, message.Hot, message.CategoryID, message.MemberCount, message.PostCount, message.CreateTime, message.Status, message.Deleted, message.JoinType); err != nil {
-			return fmt.Errorf("failed to index circle: %w", err)
-		}
-	case CircleSyncActionUpdate:
-		if err := es.UpdateCircle(message.CircleID, message.Name, message.AvatarURL, message.Description, message.Hot, message.CategoryID, message.MemberCount, message.PostCount, message.CreateTime, message.Status, message.Deleted, message.JoinType); err != nil {
-			return fmt.Errorf("failed to update circle: %w", err)
+	matched := false
+	for _, r := range s.routes {
+		if !matchTopic(r.pattern, event.Type) {
+			continue
 		}
-	case CircleSyncActionDelete:
-		if err := es.DeleteCircle(message.CircleID); err != nil {
-			return fmt.Errorf("failed to delete circle: %w", err)
+		matched = true
+		if err := r.handler(&event); err != nil {
+			return err
 		}
-	default:
-		logger.Log.Warn(fmt.Sprintf("Unknown action: %s", message.Action))
 	}
-
+	if !matched {
+		logger.Log.Warn(fmt.Sprintf("No handler for event: subscriber=%s, type=%s", s.name, event.Type))
+	}
 	return nil
 }
 
-// StartConsumerWithRetry 启动消费者，带重试机制
-func StartConsumerWithRetry() {
-	maxAttempts := conf.Config.RabbitMQ.Retry.MaxAttempts
-	attempt := 0
-
-	for {
-		attempt++
-		err := StartConsumer()
-		if err != nil {
-			logger.Log.Error(fmt.Sprintf("Failed to start consumer (attempt %d/%d): %s", attempt, maxAttempts, err.Error()))
+// matchTopic 按 topic 交换机的规则判断事件类型是否匹配模式
+func matchTopic(pattern, eventType string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(eventType, "."))
+}
 
-			if attempt >= maxAttempts {
-				logger.Log.Error("Max retry attempts reached, giving up")
-				return
+// matchWords 逐个单词匹配，# 可以匹配零个或多个单词
+func matchWords(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+	if pattern[0] == "#" {
+		for i := 0; i <= len(words); i++ {
+			if matchWords(pattern[1:], words[i:]) {
+				return true
 			}
-
-			// 等待后重试
-			waitTime := time.Duration(attempt) * 5 * time.Second
-			logger.Log.Info(fmt.Sprintf("Retrying in %v...", waitTime))
-			time.Sleep(waitTime)
-		} else {
-			// 成功启动，退出重试循环
-			logger.Log.Info("Consumer started successfully, exiting retry loop")
-			return
 		}
+		return false
+	}
+	if len(words) == 0 {
+		return false
+	}
+	if pattern[0] != "*" && pattern[0] != words[0] {
+		return false
 	}
+	return matchWords(pattern[1:], words[1:])
 }
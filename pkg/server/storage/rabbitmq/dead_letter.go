@@ -0,0 +1,214 @@
+package rabbitmq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interestBar/pkg/logger"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrUnknownSubscriber 订阅者不存在
+var ErrUnknownSubscriber = errors.New("unknown subscriber")
+
+// DeadLetter 死信队列中的消息
+type DeadLetter struct {
+	EventID    string          `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	RetryCount int             `json:"retry_count"` // 已失败的处理次数
+	LastError  string          `json:"last_error"`  // 最近一次处理失败的原因
+	DeadTime   string          `json:"dead_time"`   // 进入死信队列的时间
+	Body       json.RawMessage `json:"body"`        // 事件信封
+}
+
+// Subscribers 获取已注册的订阅者名
+func Subscribers() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	names := make([]string, 0, len(subscribers))
+	for _, s := range subscribers {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// PeekDeadLetters 查看订阅者死信队列中的前 limit 条消息，不会移除消息，同时返回死信总数
+func PeekDeadLetters(subscriber string, limit int) ([]DeadLetter, int, error) {
+	var letters []DeadLetter
+	total, err := withDeadLetterQueue(subscriber, func(ch *amqp.Channel, total int) error {
+		// 取出的消息不确认，关闭通道时全部回到队列
+		for i := 0; i < total && len(letters) < limit; i++ {
+			d, ok, err := ch.Get(deadLetterQueueName(subscriber), false)
+			if err != nil {
+				return fmt.Errorf("failed to get dead letter: %w", err)
+			}
+			if !ok {
+				break
+			}
+			letters = append(letters, newDeadLetter(d))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return letters, total, nil
+}
+
+// ReplayDeadLetters 把死信重新投递到订阅者的主队列，重置已失败次数，返回重放的数量
+// eventIDs 为空时重放最早的 limit 条，否则只重放指定事件
+func ReplayDeadLetters(subscriber string, eventIDs []string, limit int) (int, error) {
+	replayed := 0
+	_, err := withDeadLetterQueue(subscriber, func(ch *amqp.Channel, total int) error {
+		match := eventIDMatcher(eventIDs)
+		for i := 0; i < total && replayed < limit; i++ {
+			d, ok, err := ch.Get(deadLetterQueueName(subscriber), false)
+			if err != nil {
+				return fmt.Errorf("failed to get dead letter: %w", err)
+			}
+			if !ok {
+				break
+			}
+			if !match(d.MessageId) {
+				continue
+			}
+
+			headers := copyHeaders(d.Headers)
+			delete(headers, headerRetryCount)
+			delete(headers, headerLastError)
+			delete(headers, headerDeadTime)
+			if err := publish("", queueName(subscriber), republishing(d, headers)); err != nil {
+				return err
+			}
+			if err := d.Ack(false); err != nil {
+				return fmt.Errorf("failed to ack dead letter: %w", err)
+			}
+			replayed++
+		}
+		return nil
+	})
+	if replayed > 0 {
+		logger.Log.Info(fmt.Sprintf("Dead letters replayed: subscriber=%s, count=%d", subscriber, replayed))
+	}
+	return replayed, err
+}
+
+// PurgeDeadLetters 删除订阅者的死信，返回删除的数量
+// eventIDs 为空时清空整个死信队列，否则只删除指定事件
+func PurgeDeadLetters(subscriber string, eventIDs []string) (int, error) {
+	purged := 0
+	_, err := withDeadLetterQueue(subscriber, func(ch *amqp.Channel, total int) error {
+		if len(eventIDs) == 0 {
+			n, err := ch.QueuePurge(deadLetterQueueName(subscriber), false)
+			if err != nil {
+				return fmt.Errorf("failed to purge dead letter queue: %w", err)
+			}
+			purged = n
+			return nil
+		}
+
+		match := eventIDMatcher(eventIDs)
+		for i := 0; i < total; i++ {
+			d, ok, err := ch.Get(deadLetterQueueName(subscriber), false)
+			if err != nil {
+				return fmt.Errorf("failed to get dead letter: %w", err)
+			}
+			if !ok {
+				break
+			}
+			if !match(d.MessageId) {
+				continue
+			}
+			if err := d.Ack(false); err != nil {
+				return fmt.Errorf("failed to ack dead letter: %w", err)
+			}
+			purged++
+		}
+		return nil
+	})
+	if purged > 0 {
+		logger.Log.Info(fmt.Sprintf("Dead letters purged: subscriber=%s, count=%d", subscriber, purged))
+	}
+	return purged, err
+}
+
+// withDeadLetterQueue 在独立的通道上操作订阅者的死信队列，fn 的 total 为操作前的死信总数
+// 通道关闭时未确认的消息会回到队列，不影响消费者使用的通道
+func withDeadLetterQueue(subscriber string, fn func(ch *amqp.Channel, total int) error) (int, error) {
+	if !isSubscriber(subscriber) {
+		return 0, ErrUnknownSubscriber
+	}
+	c, err := currentConn()
+	if err != nil {
+		return 0, err
+	}
+
+	ch, err := c.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueDeclarePassive(deadLetterQueueName(subscriber), true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect dead letter queue: %w", err)
+	}
+	return q.Messages, fn(ch, q.Messages)
+}
+
+// isSubscriber 判断订阅者是否已注册
+func isSubscriber(name string) bool {
+	for _, s := range Subscribers() {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// eventIDMatcher 按事件ID筛选消息，ids 为空时匹配全部
+func eventIDMatcher(ids []string) func(id string) bool {
+	if len(ids) == 0 {
+		return func(string) bool { return true }
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return func(id string) bool { return set[id] }
+}
+
+// newDeadLetter 解析死信消息
+func newDeadLetter(d amqp.Delivery) DeadLetter {
+	letter := DeadLetter{
+		EventID:    d.MessageId,
+		EventType:  d.Type,
+		RetryCount: retryCount(d.Headers),
+		Body:       json.RawMessage(d.Body),
+	}
+	if s, ok := d.Headers[headerLastError].(string); ok {
+		letter.LastError = s
+	}
+	if s, ok := d.Headers[headerDeadTime].(string); ok {
+		letter.DeadTime = s
+	}
+	// 被 Broker 拒绝转入的消息没有死信时间，取 x-death 记录的时间
+	if letter.DeadTime == "" {
+		if deaths, ok := d.Headers["x-death"].([]interface{}); ok && len(deaths) > 0 {
+			if death, ok := deaths[0].(amqp.Table); ok {
+				if t, ok := death["time"].(time.Time); ok {
+					letter.DeadTime = t.Format(time.RFC3339)
+				}
+			}
+		}
+	}
+	if !json.Valid(d.Body) {
+		// 格式错误的消息体按字符串返回
+		raw, _ := json.Marshal(string(d.Body))
+		letter.Body = raw
+	}
+	return letter
+}
@@ -0,0 +1,58 @@
+package rabbitmq
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event 领域事件信封
+// Type 形如 "post.created"，同时作为路由键（加上前缀）用于 topic 路由；
+// Version 为载荷结构的版本号，载荷结构发生不兼容变更时递增，订阅者据此兼容旧消息
+type Event struct {
+	ID         string          `json:"id"`          // 事件ID，用于幂等和排查
+	Type       string          `json:"type"`        // 事件类型
+	Version    int             `json:"version"`     // 载荷版本
+	OccurredAt time.Time       `json:"occurred_at"` // 事件发生时间
+	Payload    json.RawMessage `json:"payload"`     // 事件载荷
+}
+
+// NewEvent 创建领域事件
+func NewEvent(eventType string, version int, payload interface{}) (*Event, error) {
+	id, err := newEventID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate event id: %w", err)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	return &Event{
+		ID:         id,
+		Type:       eventType,
+		Version:    version,
+		OccurredAt: time.Now(),
+		Payload:    data,
+	}, nil
+}
+
+// Decode 将事件载荷解析到 v，载荷格式错误不可重试
+func (e *Event) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return Permanent(fmt.Errorf("failed to unmarshal %s payload: %w", e.Type, err))
+	}
+	return nil
+}
+
+// newEventID 生成 32 位十六进制随机事件ID
+func newEventID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
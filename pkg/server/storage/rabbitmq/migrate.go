@@ -0,0 +1,108 @@
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+	"interestBar/pkg/logger"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// legacyRetryQueueName 获取旧版本主队列对应的重试队列名
+func legacyRetryQueueName(subscriber string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%ds", queueBaseName(subscriber), int(delay.Seconds()))
+}
+
+// migrateLegacyQueues 把旧版本（不带版本号）主队列及其重试队列中的消息转入当前主队列，并删除旧队列
+// 旧主队列先解除与事件交换机的绑定，不再接收新事件；重试队列到期的消息会回到旧主队列，所以先迁移重试队列
+// 滚动发布时旧实例仍在消费旧主队列，删除会失败，旧队列保留到下次启动消费者时再迁移
+func migrateLegacyQueues(c *amqp.Connection, s *subscriber) {
+	legacy := queueBaseName(s.name)
+	exists, err := unbindLegacyQueue(c, s, legacy)
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("Failed to unbind legacy queue %s: %s", legacy, err.Error()))
+		return
+	}
+	if !exists {
+		return
+	}
+
+	for _, delay := range retryDelays() {
+		if err := moveLegacyQueue(c, s.name, legacyRetryQueueName(s.name, delay)); err != nil {
+			logger.Log.Warn(fmt.Sprintf("Failed to migrate legacy retry queue (subscriber=%s): %s", s.name, err.Error()))
+		}
+	}
+	if err := moveLegacyQueue(c, s.name, legacy); err != nil {
+		logger.Log.Warn(fmt.Sprintf("Failed to migrate legacy queue (subscriber=%s): %s", s.name, err.Error()))
+	}
+}
+
+// unbindLegacyQueue 解除旧主队列与事件交换机的绑定，返回旧主队列是否存在
+func unbindLegacyQueue(c *amqp.Connection, s *subscriber, name string) (bool, error) {
+	ch, err := inspectQueue(c, name)
+	if err != nil || ch == nil {
+		return false, err
+	}
+	defer ch.Close()
+
+	for _, r := range s.routes {
+		if err := ch.QueueUnbind(name, routingKey(r.pattern), exchangeName(), nil); err != nil {
+			return true, fmt.Errorf("failed to unbind queue: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// moveLegacyQueue 把旧队列中的消息逐条转入订阅者的当前主队列，清空后删除旧队列，旧队列不存在时直接返回
+func moveLegacyQueue(c *amqp.Connection, subscriber, name string) error {
+	ch, err := inspectQueue(c, name)
+	if err != nil || ch == nil {
+		return err
+	}
+	defer ch.Close()
+
+	moved := 0
+	for {
+		d, ok, err := ch.Get(name, false)
+		if err != nil {
+			return fmt.Errorf("failed to get message from %s: %w", name, err)
+		}
+		if !ok {
+			break
+		}
+		if err := publish("", queueName(subscriber), republishing(d, copyHeaders(d.Headers))); err != nil {
+			return err
+		}
+		if err := d.Ack(false); err != nil {
+			return fmt.Errorf("failed to ack message from %s: %w", name, err)
+		}
+		moved++
+	}
+
+	// 仅在没有消费者且已清空时删除，避免丢失旧实例尚未确认的消息
+	if _, err := ch.QueueDelete(name, true, true, false); err != nil {
+		return fmt.Errorf("failed to delete %s (%d messages moved): %w", name, moved, err)
+	}
+	logger.Log.Info(fmt.Sprintf("Legacy queue migrated: queue=%s, moved=%d", name, moved))
+	return nil
+}
+
+// inspectQueue 在新的通道上检查队列是否存在，存在时返回该通道（由调用方关闭），不存在时返回 nil 通道
+// 被动声明不存在的队列会关闭通道，所以每次检查都使用独立的通道
+func inspectQueue(c *amqp.Connection, name string) (*amqp.Channel, error) {
+	ch, err := c.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+
+	if _, err := ch.QueueDeclarePassive(name, true, false, false, false, nil); err != nil {
+		ch.Close()
+		var amqpErr *amqp.Error
+		if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to inspect queue %s: %w", name, err)
+	}
+	return ch, nil
+}
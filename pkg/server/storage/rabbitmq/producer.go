@@ -11,163 +11,87 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-var conn *amqp.Connection
-var channel *amqp.Channel
-
-// CircleSyncMessage 圈子同步消息结构
-type CircleSyncMessage struct {
-	Action      string `json:"action"` // create, update, delete
-	CircleID    int64  `json:"circle_id"`
-	Name        string `json:"name"`
-	AvatarURL   string `json:"avatar_url,omitempty"`
-	Description string `json:"description"`
-	Hot         int    `json:"hot"`
-	CategoryID  int    `json:"category_id"`
-	MemberCount int    `json:"member_count"`
-	PostCount   int    `json:"post_count"`
-	CreateTime  string `json:"create_time"` // ISO 8601格式
-	Status      int16  `json:"status"`
-	Deleted     int16  `json:"deleted"`
-	JoinType    int16  `json:"join_type"`
+// exchangeName 获取事件交换机名
+func exchangeName() string {
+	if conf.Config.RabbitMQ.Exchange != "" {
+		return conf.Config.RabbitMQ.Exchange
+	}
+	return DefaultExchange
 }
 
-// InitRabbitMQ 初始化 RabbitMQ 连接
-func InitRabbitMQ() error {
-	url := fmt.Sprintf("amqp://%s:%s@%s:%d%s",
-		conf.Config.RabbitMQ.Username,
-		conf.Config.RabbitMQ.Password,
-		conf.Config.RabbitMQ.Host,
-		conf.Config.RabbitMQ.Port,
-		conf.Config.RabbitMQ.VHost,
-	)
+// queueName 获取订阅者的主队列名
+func queueName(subscriber string) string {
+	return queueBaseName(subscriber) + "." + QueueVersion
+}
 
-	var err error
-	conn, err = amqp.Dial(url)
-	if err != nil {
-		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+// queueBaseName 获取订阅者不带版本号的队列名，死信队列和旧版本的主队列使用
+func queueBaseName(subscriber string) string {
+	prefix := conf.Config.RabbitMQ.Queue
+	if prefix == "" {
+		prefix = DefaultQueuePrefix
 	}
+	return prefix + "." + subscriber
+}
 
-	channel, err = conn.Channel()
-	if err != nil {
-		conn.Close()
-		return fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+// routingKey 获取事件类型（或绑定模式）对应的路由键
+func routingKey(eventType string) string {
+	prefix := conf.Config.RabbitMQ.RoutingKey
+	if prefix == "" {
+		prefix = DefaultRoutingKeyPrefix
 	}
+	return prefix + "." + eventType
+}
 
-	// 声明交换机
-	err = channel.ExchangeDeclare(
-		CircleSyncExchange,
-		"direct", // 交换机类型
-		true,     // durable
-		false,    // auto-deleted
-		false,    // internal
-		false,    // no-wait
-		nil,      // arguments
-	)
+// Publish 发布领域事件，等待 Broker 确认后返回
+// 断线期间会等待重连，超时仍未恢复时返回 ErrNotConnected
+func Publish(event *Event) error {
+	body, err := json.Marshal(event)
 	if err != nil {
-		channel.Close()
-		conn.Close()
-		return fmt.Errorf("failed to declare exchange: %w", err)
+		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
-	// 声明队列
-	q, err := channel.QueueDeclare(
-		CircleSyncQueue,
-		true,  // durable
-		false, // delete when unused
-		false, // exclusive
-		false, // no-wait
-		nil,   // arguments
-	)
+	err = publish(exchangeName(), routingKey(event.Type), amqp.Publishing{
+		ContentType:  "application/json",
+		MessageId:    event.ID,
+		Type:         event.Type,
+		Timestamp:    event.OccurredAt,
+		Body:         body,
+		DeliveryMode: amqp.Persistent, // 持久化消息
+	})
 	if err != nil {
-		channel.Close()
-		conn.Close()
-		return fmt.Errorf("failed to declare queue: %w", err)
+		return err
 	}
 
-	// 绑定队列到交换机
-	err = channel.QueueBind(
-		q.Name,
-		CircleSyncRoutingKey,
-		CircleSyncExchange,
-		false,
-		nil,
-	)
-	if err != nil {
-		channel.Close()
-		conn.Close()
-		return fmt.Errorf("failed to bind queue: %w", err)
-	}
-
-	logger.Log.Info("RabbitMQ initialized successfully")
+	logger.Log.Info(fmt.Sprintf("Published event: type=%s, id=%s", event.Type, event.ID))
 	return nil
 }
 
-// PublishCircleSync 发布圈子同步消息
-func PublishCircleSync(action string, circleID int64, name string, avatarURL string, description string, hot int, categoryID int, memberCount int, postCount int, createTime string, status int16, deleted int16, joinType int16) error {
-	if channel == nil {
-		return fmt.Errorf("RabbitMQ channel is not initialized")
-	}
-
-	message := CircleSyncMessage{
-		Action:      action,
-		CircleID:    circleID,
-		Name:        name,
-		AvatarURL:   avatarURL,
-		Description: description,
-		Hot:         hot,
-		CategoryID:  categoryID,
-		MemberCount: memberCount,
-		PostCount:   postCount,
-		CreateTime:  createTime,
-		Status:      status,
-		Deleted:     deleted,
-		JoinType:    joinType,
-	}
-
-	body, err := json.Marshal(message)
+// publish 发布消息并等待 Broker 确认
+func publish(exchange, key string, msg amqp.Publishing) error {
+	ch, err := waitForChannel()
 	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+		return err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = channel.PublishWithContext(ctx,
-		CircleSyncExchange,
-		CircleSyncRoutingKey,
+	confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx,
+		exchange,
+		key,
 		false, // mandatory
 		false, // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: amqp.Persistent, // 持久化消息
-		},
+		msg,
 	)
-
 	if err != nil {
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
-
-	logger.Log.Info(fmt.Sprintf("Published circle sync message: action=%s, circle_id=%d", action, circleID))
-	return nil
-}
-
-// CloseRabbitMQ 关闭 RabbitMQ 连接
-func CloseRabbitMQ() error {
-	var err error
-	if channel != nil {
-		if e := channel.Close(); e != nil {
-			logger.Log.Error("Failed to close RabbitMQ channel: " + e.Error())
-			err = e
-		}
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for publish confirm: %w", err)
 	}
-	if conn != nil {
-		if e := conn.Close(); e != nil {
-			logger.Log.Error("Failed to close RabbitMQ connection: " + e.Error())
-			if err == nil {
-				err = e
-			}
-		}
+	if !acked {
+		return fmt.Errorf("message %s was rejected by RabbitMQ", msg.MessageId)
 	}
-	return err
+	return nil
 }
@@ -0,0 +1,243 @@
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+	"interestBar/pkg/conf"
+	"interestBar/pkg/logger"
+	"math"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// 重试相关的默认配置，配置文件中未设置(为0)时使用
+const (
+	defaultMaxAttempts     = 3   // 消息最多处理次数(含首次)
+	defaultInitialInterval = 5   // 首次重试延迟(秒)
+	defaultMultiplier      = 2.0 // 重试延迟倍数
+	defaultMaxInterval     = 600 // 最大重试延迟(秒)
+)
+
+// 消息头
+const (
+	headerRetryCount = "x-retry-count" // 已失败的处理次数
+	headerLastError  = "x-last-error"  // 最近一次处理失败的原因
+	headerDeadTime   = "x-dead-time"   // 进入死信队列的时间
+)
+
+// permanentError 不可重试的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 标记错误不可重试（如消息格式错误），处理函数返回它时消息直接进入死信队列
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// isPermanent 判断错误是否不可重试
+func isPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// maxAttempts 获取消息最多处理次数
+func maxAttempts() int {
+	if conf.Config.RabbitMQ.Retry.MaxAttempts > 0 {
+		return conf.Config.RabbitMQ.Retry.MaxAttempts
+	}
+	return defaultMaxAttempts
+}
+
+// retryDelay 计算第 attempt 次处理失败后的重试延迟（指数退避）
+func retryDelay(attempt int) time.Duration {
+	cfg := conf.Config.RabbitMQ.Retry
+	initial := cfg.InitialInterval
+	if initial <= 0 {
+		initial = defaultInitialInterval
+	}
+	multiplier := cfg.Multiplier
+	if multiplier < 1 {
+		multiplier = defaultMultiplier
+	}
+	maxInterval := cfg.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxInterval
+	}
+
+	seconds := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if seconds > float64(maxInterval) {
+		seconds = float64(maxInterval)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// retryDelays 获取所有需要的重试延迟（去重），每个延迟对应一个重试队列
+func retryDelays() []time.Duration {
+	var delays []time.Duration
+	seen := make(map[time.Duration]bool)
+	for attempt := 1; attempt < maxAttempts(); attempt++ {
+		d := retryDelay(attempt)
+		if !seen[d] {
+			seen[d] = true
+			delays = append(delays, d)
+		}
+	}
+	return delays
+}
+
+// deadLetterExchangeName 获取死信交换机名
+func deadLetterExchangeName() string {
+	return exchangeName() + ".dlx"
+}
+
+// deadLetterQueueName 获取订阅者的死信队列名，死信队列没有参数，不随主队列版本变化
+func deadLetterQueueName(subscriber string) string {
+	return queueBaseName(subscriber) + ".dead"
+}
+
+// retryQueueName 获取订阅者指定延迟的重试队列名，延迟写进队列名，修改退避配置后会使用新的队列
+func retryQueueName(subscriber string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%ds", queueName(subscriber), int(delay.Seconds()))
+}
+
+// declareSubscriberQueues 声明订阅者的主队列、重试队列和死信队列
+// 主队列：处理失败时由消费者转入重试队列；被拒绝的消息由 Broker 转入死信交换机
+// 重试队列：消息到期(TTL)后经默认交换机回到主队列，不会再分发给其他订阅者
+// 死信队列：超过最大处理次数或不可重试的消息，等待管理员查看、重放或清空
+func declareSubscriberQueues(ch *amqp.Channel, subscriber string) error {
+	dlx := deadLetterExchangeName()
+	if err := ch.ExchangeDeclare(dlx, "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter exchange: %w", err)
+	}
+	if _, err := ch.QueueDeclare(deadLetterQueueName(subscriber), true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter queue: %w", err)
+	}
+	if err := ch.QueueBind(deadLetterQueueName(subscriber), subscriber, dlx, false, nil); err != nil {
+		return fmt.Errorf("failed to bind dead letter queue: %w", err)
+	}
+
+	_, err := ch.QueueDeclare(
+		queueName(subscriber),
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		amqp.Table{
+			"x-dead-letter-exchange":    dlx,
+			"x-dead-letter-routing-key": subscriber,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+
+	for _, delay := range retryDelays() {
+		_, err := ch.QueueDeclare(
+			retryQueueName(subscriber, delay),
+			true,  // durable
+			false, // delete when unused
+			false, // exclusive
+			false, // no-wait
+			amqp.Table{
+				"x-message-ttl":             delay.Milliseconds(),
+				"x-dead-letter-exchange":    "", // 默认交换机，按队列名直接投递
+				"x-dead-letter-routing-key": queueName(subscriber),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare retry queue: %w", err)
+		}
+	}
+	return nil
+}
+
+// retryCount 读取消息已失败的处理次数
+func retryCount(headers amqp.Table) int {
+	switch v := headers[headerRetryCount].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// handleFailure 处理失败的消息：未超过最大处理次数的转入对应延迟的重试队列，否则转入死信队列
+func (s *subscriber) handleFailure(d amqp.Delivery, cause error) {
+	attempts := retryCount(d.Headers) + 1
+
+	if !isPermanent(cause) && attempts < maxAttempts() {
+		delay := retryDelay(attempts)
+		logger.Log.Warn(fmt.Sprintf("Failed to process event (subscriber=%s, attempt %d/%d), retry in %s: %s",
+			s.name, attempts, maxAttempts(), delay, cause.Error()))
+
+		headers := copyHeaders(d.Headers)
+		headers[headerRetryCount] = int32(attempts)
+		if err := publish("", retryQueueName(s.name, delay), republishing(d, headers)); err != nil {
+			// 转入重试队列失败，重新入队等待下次处理
+			logger.Log.Error("Failed to schedule retry: " + err.Error())
+			d.Nack(false, true)
+			return
+		}
+		d.Ack(false)
+		return
+	}
+
+	logger.Log.Error(fmt.Sprintf("Event dead-lettered (subscriber=%s, attempts=%d): %s", s.name, attempts, cause.Error()))
+
+	headers := copyHeaders(d.Headers)
+	headers[headerRetryCount] = int32(attempts)
+	headers[headerLastError] = truncate(cause.Error(), 1000)
+	headers[headerDeadTime] = time.Now().Format(time.RFC3339)
+	if err := publish(deadLetterExchangeName(), s.name, republishing(d, headers)); err != nil {
+		// 带失败原因转入死信队列失败时，拒绝消息，由 Broker 按队列的死信配置转入
+		logger.Log.Error("Failed to publish dead letter: " + err.Error())
+		d.Nack(false, false)
+		return
+	}
+	d.Ack(false)
+}
+
+// republishing 复制消息用于重新发布
+func republishing(d amqp.Delivery, headers amqp.Table) amqp.Publishing {
+	return amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		MessageId:    d.MessageId,
+		Type:         d.Type,
+		Timestamp:    d.Timestamp,
+		Body:         d.Body,
+		DeliveryMode: amqp.Persistent,
+	}
+}
+
+// copyHeaders 复制消息头，去掉 Broker 添加的死信记录
+func copyHeaders(headers amqp.Table) amqp.Table {
+	copied := amqp.Table{}
+	for k, v := range headers {
+		if k == "x-death" || k == "x-first-death-exchange" || k == "x-first-death-queue" || k == "x-first-death-reason" {
+			continue
+		}
+		copied[k] = v
+	}
+	return copied
+}
+
+// truncate 按字符截断字符串
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
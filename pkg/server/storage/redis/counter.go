@@ -0,0 +1,170 @@
+package redis
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 写回缓冲计数器的键
+// 高频计数（如点赞数）先累加到 Redis Hash 中，由后台任务批量写回数据库，避免热点行锁竞争
+const (
+	// PostLikeCounterKey 帖子点赞数增量，field 为帖子ID
+	PostLikeCounterKey = "counter:post:like"
+	// CommentLikeCounterKey 评论点赞数增量，field 为评论ID
+	CommentLikeCounterKey = "counter:comment:like"
+	// PollOptionCounterKey 投票选项得票数增量，field 为选项ID
+	PollOptionCounterKey = "counter:poll:option"
+	// PollVoterCounterKey 投票参与人数增量，field 为帖子ID
+	PollVoterCounterKey = "counter:poll:voter"
+)
+
+const (
+	// flushingSuffix 正在写回中的增量快照键后缀
+	flushingSuffix = ":flushing"
+	// lockSuffix 写回锁键后缀，同一计数器同时只允许一个实例写回
+	lockSuffix = ":lock"
+	// batchField 快照中记录写回批次号的字段，数据库据此判断该批次是否已写回
+	batchField = "_batch"
+)
+
+// takeCountersScript 原子地把增量转移到快照键并为快照分配批次号，返回快照全部内容
+// 已有快照（上次写回失败或写回后未来得及删除）时直接返回，沿用其批次号
+var takeCountersScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	if redis.call('EXISTS', KEYS[1]) == 0 then
+		return {}
+	end
+	redis.call('RENAME', KEYS[1], KEYS[2])
+end
+redis.call('HSETNX', KEYS[2], ARGV[1], ARGV[2])
+return redis.call('HGETALL', KEYS[2])
+`)
+
+// unlockScript 只释放自己持有的锁
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// IncrCounter 累加计数器增量
+func IncrCounter(key string, id int64, delta int64) error {
+	return Client.HIncrBy(ctx, key, strconv.FormatInt(id, 10), delta).Err()
+}
+
+// IncrPollVoteCounters 在同一个 MULTI 中累加一次投票的选项得票数和参与人数增量，要么全部累加，要么都不累加
+func IncrPollVoteCounters(postID int64, optionIDs []int64) error {
+	_, err := Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range optionIDs {
+			pipe.HIncrBy(ctx, PollOptionCounterKey, strconv.FormatInt(id, 10), 1)
+		}
+		pipe.HIncrBy(ctx, PollVoterCounterKey, strconv.FormatInt(postID, 10), 1)
+		return nil
+	})
+	return err
+}
+
+// GetCounters 批量获取尚未写回数据库的计数器增量（包括正在写回中的部分）
+// 快照在写回事务提交后立即删除；写回实例在两者之间崩溃时，快照会在下一次写回时识别为已写回并删除
+func GetCounters(key string, ids []int64) (map[int64]int64, error) {
+	deltas := make(map[int64]int64, len(ids))
+	if len(ids) == 0 {
+		return deltas, nil
+	}
+
+	fields := make([]string, 0, len(ids))
+	for _, id := range ids {
+		fields = append(fields, strconv.FormatInt(id, 10))
+	}
+
+	for _, k := range []string{key, key + flushingSuffix} {
+		values, err := Client.HMGet(ctx, k, fields...).Result()
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				deltas[ids[i]] += n
+			}
+		}
+	}
+
+	return deltas, nil
+}
+
+// LockCounter 获取计数器的写回锁，返回的锁令牌用于释放；锁被其他实例持有时返回 false
+// 锁超时后其他实例可以接管，重复写回由批次号保证幂等
+func LockCounter(key string, ttl time.Duration) (string, bool, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", false, err
+	}
+	ok, err := Client.SetNX(ctx, key+lockSuffix, token, ttl).Result()
+	if err != nil {
+		return "", false, err
+	}
+	return token, ok, nil
+}
+
+// UnlockCounter 释放计数器的写回锁
+func UnlockCounter(key, token string) error {
+	return unlockScript.Run(ctx, Client, []string{key + lockSuffix}, token).Err()
+}
+
+// TakeCounters 取出当前累积的全部增量用于写回，同时返回快照的批次号
+// 增量会先被原子地转移到快照键中，写回期间的新增量继续累加到原键，互不影响；
+// 上一次写回遗留的快照会被优先返回，批次号不变，调用方需在写回事务中登记批次号以免重复写回。
+// 写回成功后需调用 AckCounters 删除快照
+func TakeCounters(key string) (string, map[int64]int64, error) {
+	batch, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	values, err := takeCountersScript.Run(ctx, Client, []string{key, key + flushingSuffix}, batchField, batch).StringSlice()
+	if err != nil && err != redis.Nil {
+		return "", nil, err
+	}
+
+	deltas := make(map[int64]int64, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		field, value := values[i], values[i+1]
+		if field == batchField {
+			batch = value
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n == 0 {
+			continue
+		}
+		deltas[id] = n
+	}
+	return batch, deltas, nil
+}
+
+// randomToken 生成随机令牌
+func randomToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// AckCounters 写回成功后删除增量快照
+func AckCounters(key string) error {
+	return Client.Del(ctx, key+flushingSuffix).Err()
+}
@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 首页信息流缓存
+// 每个用户的信息流以 ZSet 缓存（member 为帖子ID，score 为排序分值），读时聚合、过期后重建
+const (
+	// FeedKeyPrefix 首页信息流缓存键前缀，完整键为 feed:home:{userID}
+	FeedKeyPrefix = "feed:home:"
+	// FeedEndMarker 信息流结束标记，缓存已包含全部条目时写入，score 为 0 排在最后
+	FeedEndMarker = 0
+)
+
+// FeedEntry 信息流缓存条目
+type FeedEntry struct {
+	PostID int64
+	Score  int64
+}
+
+// feedKey 获取用户的信息流缓存键
+func feedKey(userID int64) string {
+	return FeedKeyPrefix + strconv.FormatInt(userID, 10)
+}
+
+// feedMember 将帖子ID补齐为定长字符串，使同分值条目的字典序与ID大小一致
+func feedMember(postID int64) string {
+	return fmt.Sprintf("%020d", postID)
+}
+
+// SetFeed 重建用户的信息流缓存，complete 表示 entries 已包含全部条目（会追加结束标记）
+func SetFeed(userID int64, entries []FeedEntry, complete bool, expiration time.Duration) error {
+	key := feedKey(userID)
+
+	members := make([]redis.Z, 0, len(entries)+1)
+	for _, e := range entries {
+		members = append(members, redis.Z{Score: float64(e.Score), Member: feedMember(e.PostID)})
+	}
+	if complete {
+		members = append(members, redis.Z{Score: 0, Member: feedMember(FeedEndMarker)})
+	}
+
+	_, err := Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(members) > 0 {
+			pipe.ZAdd(ctx, key, members...)
+			pipe.Expire(ctx, key, expiration)
+		}
+		return nil
+	})
+	return err
+}
+
+// GetFeed 从缓存中读取信息流的一页（按分值、帖子ID倒序）
+// hasCursor 为 true 时只返回排在 (cursorScore, cursorID) 之后的条目；缓存不存在时 exists 返回 false
+func GetFeed(userID int64, hasCursor bool, cursorScore, cursorID int64, limit int) (entries []FeedEntry, exists bool, err error) {
+	key := feedKey(userID)
+
+	n, err := Client.Exists(ctx, key).Result()
+	if err != nil || n == 0 {
+		return nil, false, err
+	}
+
+	max := "+inf"
+	if hasCursor {
+		// 与游标同分值的条目单独取出，按帖子ID过滤
+		ties, err := Client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+			Min: strconv.FormatInt(cursorScore, 10),
+			Max: strconv.FormatInt(cursorScore, 10),
+		}).Result()
+		if err != nil {
+			return nil, true, err
+		}
+		for _, z := range toFeedEntries(ties) {
+			if z.PostID < cursorID {
+				entries = append(entries, z)
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].PostID > entries[j].PostID })
+		if len(entries) >= limit {
+			return entries[:limit], true, nil
+		}
+		max = "(" + strconv.FormatInt(cursorScore, 10)
+	}
+
+	rest, err := Client.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   max,
+		Count: int64(limit - len(entries)),
+	}).Result()
+	if err != nil {
+		return nil, true, err
+	}
+	return append(entries, toFeedEntries(rest)...), true, nil
+}
+
+// DelFeed 删除用户的信息流缓存（加入圈子、修改置顶设置后调用）
+func DelFeed(userID int64) error {
+	return Client.Del(ctx, feedKey(userID)).Err()
+}
+
+// toFeedEntries 将 ZSet 结果转换为信息流条目
+func toFeedEntries(zs []redis.Z) []FeedEntry {
+	entries := make([]FeedEntry, 0, len(zs))
+	for _, z := range zs {
+		s, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		postID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, FeedEntry{PostID: postID, Score: int64(z.Score)})
+	}
+	return entries
+}
@@ -0,0 +1,135 @@
+package subscriber
+
+import (
+	"fmt"
+	"interestBar/pkg/server/event"
+	"interestBar/pkg/server/model"
+	"interestBar/pkg/server/storage/db/pgsql"
+	es "interestBar/pkg/server/storage/elasticsearch"
+	"interestBar/pkg/server/storage/rabbitmq"
+
+	"gorm.io/gorm"
+)
+
+// searchSubscriber 搜索索引订阅者名
+const searchSubscriber = "search"
+
+// registerSearch 注册搜索索引订阅者：圈子、帖子变更后同步 Elasticsearch；
+// 成员变动会改变圈子成员数，评论变动会改变帖子评论数，也需要重新同步
+func registerSearch() {
+	rabbitmq.Subscribe(searchSubscriber, "circle.*", handleCircleIndex)
+	rabbitmq.Subscribe(searchSubscriber, "member.*", handleMemberIndex)
+	rabbitmq.Subscribe(searchSubscriber, "post.*", handlePostIndex)
+	rabbitmq.Subscribe(searchSubscriber, "comment.*", handleCommentIndex)
+}
+
+// handleCircleIndex 处理圈子事件
+func handleCircleIndex(e *rabbitmq.Event) error {
+	var payload event.CirclePayload
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	switch e.Type {
+	case event.CircleDeleted:
+		return deleteCircle(payload.CircleID)
+	case event.CircleUpdated:
+		return syncCircle(payload.CircleID, es.UpdateCircle)
+	}
+	return syncCircle(payload.CircleID, es.IndexCircle)
+}
+
+// handleMemberIndex 处理成员事件，同步圈子成员数
+func handleMemberIndex(e *rabbitmq.Event) error {
+	var payload event.MemberPayload
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	return syncCircle(payload.CircleID, es.IndexCircle)
+}
+
+// handlePostIndex 处理帖子事件
+func handlePostIndex(e *rabbitmq.Event) error {
+	var payload event.PostPayload
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	return syncPost(payload.PostID)
+}
+
+// handleCommentIndex 处理评论事件，同步帖子评论数
+func handleCommentIndex(e *rabbitmq.Event) error {
+	var payload event.CommentPayload
+	if err := e.Decode(&payload); err != nil {
+		return err
+	}
+	return syncPost(payload.PostID)
+}
+
+// circleWriter 写入圈子索引的方式：es.IndexCircle 整体覆盖，es.UpdateCircle 更新已有文档
+type circleWriter func(circleID int64, name string, avatarURL string, description string, hot int, categoryID int, memberCount int, postCount int, createTime string, status int16, deleted int16, joinType int16) error
+
+// syncCircle 读取圈子最新数据并写入索引，圈子不存在时删除索引
+func syncCircle(circleID int64, write circleWriter) error {
+	circle, err := model.GetCircleByID(pgsql.DB, circleID)
+	if err == gorm.ErrRecordNotFound {
+		return deleteCircle(circleID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load circle: %w", err)
+	}
+
+	createTime := circle.CreateTime.Format("2006-01-02T15:04:05Z07:00")
+	if err := write(circle.ID, circle.Name, circle.AvatarURL, circle.Description, circle.Hot, circle.CategoryID, circle.MemberCount, circle.PostCount, createTime, circle.Status, circle.Deleted, circle.JoinType); err != nil {
+		return fmt.Errorf("failed to index circle: %w", err)
+	}
+	return nil
+}
+
+// deleteCircle 删除圈子及其全部帖子的索引
+// 解散圈子时帖子在同一事务中被批量删除，不会逐个发出帖子事件，需要按圈子一并清理
+func deleteCircle(circleID int64) error {
+	if err := es.DeleteCircle(circleID); err != nil {
+		return fmt.Errorf("failed to delete circle: %w", err)
+	}
+	if err := es.DeletePostsByCircle(circleID); err != nil {
+		return fmt.Errorf("failed to delete circle posts: %w", err)
+	}
+	return nil
+}
+
+// syncPost 读取帖子最新状态，已删除或草稿删除索引，其余状态建立索引（搜索时只返回已发布的帖子）
+func syncPost(postID int64) error {
+	post, err := model.GetPostByID(pgsql.DB, postID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to load post: %w", err)
+	}
+	if post == nil || post.Status == model.PostStatusDraft {
+		if err := es.DeletePost(postID); err != nil {
+			return fmt.Errorf("failed to delete post: %w", err)
+		}
+		return nil
+	}
+
+	doc := es.PostDocument{
+		ID:           post.ID,
+		CircleID:     post.CircleID,
+		UserID:       post.UserID,
+		Type:         post.Type,
+		Title:        post.Title,
+		Summary:      post.Summary,
+		Content:      post.Content,
+		Status:       post.Status,
+		Deleted:      post.Deleted,
+		IsEssence:    post.IsEssence,
+		ViewCount:    post.ViewCount,
+		LikeCount:    post.LikeCount,
+		CommentCount: post.CommentCount,
+		CollectCount: post.CollectCount,
+		Hot:          post.Hot,
+		CreateTime:   post.CreateTime.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if err := es.IndexPost(doc); err != nil {
+		return fmt.Errorf("failed to index post: %w", err)
+	}
+	return nil
+}
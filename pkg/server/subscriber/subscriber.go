@@ -0,0 +1,10 @@
+package subscriber
+
+// 领域事件订阅者
+// 每个订阅者在 RabbitMQ 上有独立的队列，只绑定自己关心的事件类型，
+// 新增订阅者（如通知、计数）只需在这里注册，不影响已有订阅者的消费
+
+// RegisterAll 注册所有事件订阅者，需要在 rabbitmq.StartConsumer 之前调用
+func RegisterAll() {
+	registerSearch()
+}